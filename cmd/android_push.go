@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Delivery methods for push notifications on Android emulators.
+const (
+	AndroidPushAuto         = "auto"
+	AndroidPushNotification = "notification"
+	AndroidPushBroadcast    = "broadcast"
+
+	// fcmReceiveAction is the intent action Firebase Cloud Messaging delivers messages with.
+	fcmReceiveAction = "com.google.android.c2dm.intent.RECEIVE"
+	// defaultAndroidPushTag is the notification tag used when the payload has no thread-id.
+	defaultAndroidPushTag = "sim-push"
+)
+
+// AndroidPush is an APNs payload translated for delivery to an Android emulator.
+type AndroidPush struct {
+	Title  string
+	Body   string
+	Tag    string            // From aps.thread-id; notifications with the same tag replace each other.
+	Silent bool              // aps.content-available without an alert, i.e. a background data message.
+	Data   map[string]string // Custom (non-aps) keys; non-string values are JSON encoded.
+}
+
+// TranslateAPNsPayload maps an APNs payload to an AndroidPush:
+//
+//   - aps.alert (string)            → Body
+//   - aps.alert.title / .subtitle   → Title ("title - subtitle")
+//   - aps.alert.body                → Body
+//   - aps.thread-id                 → Tag
+//   - aps.content-available = 1     → Silent, when there is no alert
+//   - other top-level keys          → Data (except "Simulator Target Bundle")
+//
+// aps.badge, aps.sound, aps.category and aps.mutable-content have no shell equivalent and are ignored.
+func TranslateAPNsPayload(content []byte) (AndroidPush, error) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return AndroidPush{}, fmt.Errorf("invalid JSON payload: %w", err)
+	}
+
+	var aps struct {
+		Alert            json.RawMessage `json:"alert"`
+		ThreadID         string          `json:"thread-id"`
+		ContentAvailable int             `json:"content-available"`
+	}
+	if raw, ok := payload["aps"]; ok {
+		if err := json.Unmarshal(raw, &aps); err != nil {
+			return AndroidPush{}, fmt.Errorf("invalid aps dictionary: %w", err)
+		}
+	}
+
+	p := AndroidPush{Tag: aps.ThreadID, Data: map[string]string{}}
+	if p.Tag == "" {
+		p.Tag = defaultAndroidPushTag
+	}
+
+	if len(aps.Alert) > 0 {
+		var text string
+		var alert struct {
+			Title    string `json:"title"`
+			Subtitle string `json:"subtitle"`
+			Body     string `json:"body"`
+		}
+		if err := json.Unmarshal(aps.Alert, &text); err == nil {
+			p.Body = text
+		} else if err := json.Unmarshal(aps.Alert, &alert); err == nil {
+			p.Title, p.Body = alert.Title, alert.Body
+			if alert.Subtitle != "" {
+				p.Title = strings.TrimPrefix(p.Title+" - "+alert.Subtitle, " - ")
+			}
+		} else {
+			return AndroidPush{}, fmt.Errorf("invalid aps.alert: %w", err)
+		}
+	}
+	p.Silent = aps.ContentAvailable == 1 && p.Title == "" && p.Body == ""
+
+	for key, raw := range payload {
+		if key == "aps" || key == simulatorTargetBundleKey {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			p.Data[key] = s
+		} else {
+			p.Data[key] = string(raw)
+		}
+	}
+
+	return p, nil
+}
+
+// Via resolves AndroidPushAuto: silent pushes are broadcast as data messages, others are posted.
+func (p AndroidPush) Via(via string) string {
+	if via != AndroidPushAuto && via != "" {
+		return via
+	}
+	if p.Silent {
+		return AndroidPushBroadcast
+	}
+
+	return AndroidPushNotification
+}
+
+// NotificationArgs returns the 'adb shell' arguments that post p with 'cmd notification post'.
+// The notification is posted by the shell, not by the target app.
+func (p AndroidPush) NotificationArgs() []string {
+	args := []string{"cmd", "notification", "post"}
+	if p.Title != "" {
+		args = append(args, "-t", shellQuote(p.Title))
+	}
+
+	body := p.Body
+	if body == "" {
+		body = p.Title
+	}
+
+	return append(args, shellQuote(p.Tag), shellQuote(body))
+}
+
+// BroadcastArgs returns the 'adb shell' arguments that deliver p to pkg's FCM receiver.
+// The alert is sent as the gcm.notification.* extras FCM uses for notification messages,
+// and every data key becomes a string extra.
+func (p AndroidPush) BroadcastArgs(pkg string) []string {
+	args := []string{"am", "broadcast", "-a", fcmReceiveAction, "-p", pkg}
+
+	extras := map[string]string{}
+	for k, v := range p.Data {
+		extras[k] = v
+	}
+	if p.Title != "" {
+		extras["gcm.notification.title"] = p.Title
+	}
+	if p.Body != "" {
+		extras["gcm.notification.body"] = p.Body
+	}
+
+	keys := make([]string, 0, len(extras))
+	for k := range extras {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		args = append(args, "--es", shellQuote(k), shellQuote(extras[k]))
+	}
+
+	return args
+}
+
+// sendAndroidPush delivers an APNs payload to pkg on an Android emulator.
+func sendAndroidPush(udid, pkg string, content []byte, via string) (string, error) {
+	p, err := TranslateAPNsPayload(content)
+	if err != nil {
+		return "", err
+	}
+
+	via = p.Via(via)
+
+	var args []string
+	switch via {
+	case AndroidPushNotification:
+		if p.Title == "" && p.Body == "" {
+			return "", fmt.Errorf("payload has no alert to post as a notification; use --android-via %s", AndroidPushBroadcast) //nolint:err113
+		}
+		args = p.NotificationArgs()
+	case AndroidPushBroadcast:
+		args = p.BroadcastArgs(pkg)
+	default:
+		return "", fmt.Errorf("unknown --android-via %q (use %s, %s or %s)", via, //nolint:err113
+			AndroidPushAuto, AndroidPushNotification, AndroidPushBroadcast)
+	}
+
+	if err := packageExecutor.Run(CmdAdb, append([]string{"-s", udid, "shell"}, args...)...); err != nil {
+		return "", fmt.Errorf("failed to send push notification: %w", err)
+	}
+
+	return via, nil
+}
@@ -0,0 +1,678 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// backupManifestName is the archive entry holding the AppBackupManifest.
+	backupManifestName = "manifest.json"
+	// backupDataDir is the archive directory holding the app's data container.
+	backupDataDir = "data"
+	// androidRestoreTmp is where restore archives are staged on Android devices.
+	androidRestoreTmp = "/data/local/tmp/sim-restore.tar"
+)
+
+// appIDPattern matches valid iOS bundle IDs and Android package names. It also
+// guards the IDs that end up interpolated into adb shell command strings.
+var appIDPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.\-]*$`)
+
+// AppBackupManifest describes the app data stored in a backup archive.
+type AppBackupManifest struct {
+	App        string    `json:"app"`
+	Version    string    `json:"version,omitempty"`
+	Device     string    `json:"device"`
+	DeviceUDID string    `json:"deviceUdid"`
+	Platform   string    `json:"platform"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+var appCmd = &cobra.Command{
+	Use:   "app",
+	Short: "Manage installed app data",
+	Long: `Back up and restore the data container of an installed app.
+
+iOS simulators archive the app's data container. Android emulators archive
+/data/data/<package> through run-as, so the app must be debuggable.`,
+}
+
+var appBackupCmd = &cobra.Command{
+	Use:   "backup [device-name-or-udid] <bundle-id-or-package> <output.tar.gz>",
+	Short: "Archive an app's data container",
+	Long: `Archive the data container of an installed app into a .tar.gz file.
+
+The archive includes a manifest with the app version, device and platform.
+
+Examples:
+  sim app backup com.example.app state.tar.gz
+  sim app backup "Pixel_7_API_34" com.example.app state.tar.gz`,
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID, appID, outputPath string
+		if len(args) == 2 {
+			appID = args[0]
+			outputPath = args[1]
+		} else {
+			deviceID = args[0]
+			appID = args[1]
+			outputPath = args[2]
+		}
+
+		return BackupAppData(deviceID, appID, outputPath)
+	},
+}
+
+var appRestoreCmd = &cobra.Command{
+	Use:   "restore [device-name-or-udid] <bundle-id-or-package> <backup.tar.gz>",
+	Short: "Restore an app's data container from a backup",
+	Long: `Stop the app and replace its data container with the contents of a backup
+created by 'sim app backup'.`,
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID, appID, archivePath string
+		if len(args) == 2 {
+			appID = args[0]
+			archivePath = args[1]
+		} else {
+			deviceID = args[0]
+			appID = args[1]
+			archivePath = args[2]
+		}
+
+		return RestoreAppData(deviceID, appID, archivePath)
+	},
+}
+
+func init() {
+	appCmd.AddCommand(appBackupCmd)
+	appCmd.AddCommand(appRestoreCmd)
+}
+
+// validateAppID returns an error if appID is not a plausible bundle ID or package name.
+func validateAppID(appID string) error {
+	if !appIDPattern.MatchString(appID) {
+		return fmt.Errorf("%w: %q", ErrInvalidAppID, appID)
+	}
+
+	return nil
+}
+
+// BackupAppData archives the data container of appID on the given device to outputPath.
+// Pass an empty deviceID to use the first running device.
+func BackupAppData(deviceID, appID, outputPath string) error {
+	if err := validateAppID(appID); err != nil {
+		return err
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	manifest := AppBackupManifest{
+		App:        appID,
+		Device:     name,
+		DeviceUDID: udid,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	err = RunSpinner(fmt.Sprintf("Backing up %s data from '%s'...", appID, name), func() error {
+		if isAndroid {
+			manifest.Platform = PlatformAndroid
+			manifest.Version = androidAppVersion(udid, appID)
+
+			data, dataErr := androidAppDataTar(udid, appID)
+			if dataErr != nil {
+				return dataErr
+			}
+
+			return writeAppBackup(outputPath, &manifest, func(tw *tar.Writer) error {
+				return copyTarEntries(tw, tar.NewReader(bytes.NewReader(data)), backupDataDir)
+			})
+		}
+
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+		manifest.Platform = PlatformIOS
+		manifest.Version = iosAppVersion(udid, appID)
+
+		container, containerErr := iosAppContainer(udid, appID, "data")
+		if containerErr != nil {
+			return containerErr
+		}
+
+		return writeAppBackup(outputPath, &manifest, func(tw *tar.Writer) error {
+			return addDirToTar(tw, container, backupDataDir)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("App data for '%s' saved to: %s", appID, outputPath))
+
+	return nil
+}
+
+// RestoreAppData stops appID and replaces its data container with the contents of archivePath.
+// Pass an empty deviceID to use the first running device.
+func RestoreAppData(deviceID, appID, archivePath string) error {
+	if err := validateAppID(appID); err != nil {
+		return err
+	}
+
+	manifest, err := ReadAppBackupManifest(archivePath)
+	if err != nil {
+		return err
+	}
+	if manifest.App != appID {
+		return fmt.Errorf("%w: backup is for %q, not %q", ErrBackupMismatch, manifest.App, appID)
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	platform := PlatformIOS
+	if isAndroid {
+		platform = PlatformAndroid
+	}
+	if manifest.Platform != platform {
+		return fmt.Errorf("%w: backup was taken on %s, target device is %s", ErrBackupMismatch, manifest.Platform, platform)
+	}
+
+	err = RunSpinner(fmt.Sprintf("Restoring %s data on '%s'...", appID, name), func() error {
+		if isAndroid {
+			return restoreAndroidAppData(udid, appID, archivePath)
+		}
+
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+
+		return restoreIOSAppData(udid, appID, archivePath)
+	})
+	if err != nil {
+		return err
+	}
+
+	if manifest.Version != "" {
+		PrintInfo(fmt.Sprintf("Backup taken from %s version %s on '%s'.", appID, manifest.Version, manifest.Device))
+	}
+	PrintSuccess(fmt.Sprintf("App data for '%s' restored on '%s'.", appID, name))
+
+	return nil
+}
+
+// ReadAppBackupManifest reads the manifest from a backup archive created by BackupAppData.
+func ReadAppBackupManifest(archivePath string) (*AppBackupManifest, error) {
+	var manifest *AppBackupManifest
+
+	err := walkAppBackup(archivePath, func(hdr *tar.Header, r io.Reader) error {
+		if hdr.Name != backupManifestName {
+			return nil
+		}
+
+		var m AppBackupManifest
+		if err := json.NewDecoder(r).Decode(&m); err != nil {
+			return fmt.Errorf("%w: invalid manifest: %w", ErrInvalidBackup, err)
+		}
+		manifest = &m
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("%w: %s has no %s", ErrInvalidBackup, archivePath, backupManifestName)
+	}
+
+	return manifest, nil
+}
+
+// iosAppContainer returns the host path of an app container on an iOS simulator.
+// kind is one of "app", "data", "groups" or a specific group identifier.
+func iosAppContainer(udid, appID, kind string) (string, error) {
+	out, err := packageExecutor.Output(CmdXCrun, CmdSimctl, "get_app_container", udid, appID, kind)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %s container for %s: %w", kind, appID, err)
+	}
+
+	container := strings.TrimSpace(string(out))
+	if container == "" {
+		return "", fmt.Errorf("failed to locate %s container for %s: %w", kind, appID, ErrAppNotInstalled)
+	}
+
+	return container, nil
+}
+
+// iosAppVersion returns the CFBundleShortVersionString of an installed iOS app, or "" if unknown.
+func iosAppVersion(udid, appID string) string {
+	bundle, err := iosAppContainer(udid, appID, "app")
+	if err != nil {
+		return ""
+	}
+
+	out, err := packageExecutor.Output(CmdPlutil, "-extract", "CFBundleShortVersionString", "raw", "-o", "-",
+		filepath.Join(bundle, "Info.plist"))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// androidAppVersion returns the versionName of an installed Android package, or "" if unknown.
+func androidAppVersion(udid, pkg string) string {
+	out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "dumpsys", "package", pkg)
+	if err != nil {
+		return ""
+	}
+
+	for line := range strings.SplitSeq(string(out), "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "versionName="); ok {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// androidAppDataTar streams the app's data directory from the device as a tar archive.
+func androidAppDataTar(udid, pkg string) ([]byte, error) {
+	out, err := packageExecutor.Output(CmdAdb, "-s", udid, "exec-out", "run-as", pkg, "tar", "-cf", "-", ".")
+	if bytes.HasPrefix(out, []byte("run-as:")) {
+		return nil, fmt.Errorf("%w: %s", ErrAppNotDebuggable, strings.TrimSpace(string(out)))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read app data from Android emulator: %w", err)
+	}
+
+	return out, nil
+}
+
+// restoreIOSAppData terminates the app and replaces its data container contents. The backup
+// is unpacked into a staging directory first, so a corrupt archive leaves the container intact.
+func restoreIOSAppData(udid, appID, archivePath string) error {
+	// Terminate fails when the app is not running, which is fine.
+	_ = packageExecutor.Run(CmdXCrun, CmdSimctl, "terminate", udid, appID)
+
+	container, err := iosAppContainer(udid, appID, "data")
+	if err != nil {
+		return err
+	}
+
+	// Stage inside the container so the final renames stay on one filesystem.
+	staging, err := os.MkdirTemp(container, ".sim-restore-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	if err := ExtractAppBackup(archivePath, staging); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(container)
+	if err != nil {
+		return fmt.Errorf("failed to read data container: %w", err)
+	}
+	for _, e := range entries {
+		if e.Name() == filepath.Base(staging) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(container, e.Name())); err != nil {
+			return fmt.Errorf("failed to clear data container: %w", err)
+		}
+	}
+
+	restored, err := os.ReadDir(staging)
+	if err != nil {
+		return fmt.Errorf("failed to read restored data: %w", err)
+	}
+	for _, e := range restored {
+		if err := os.Rename(filepath.Join(staging, e.Name()), filepath.Join(container, e.Name())); err != nil {
+			return fmt.Errorf("failed to restore data container: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ExtractAppBackup unpacks the data directory of the backup at archivePath into dest.
+// Entries that would escape dest, directly or through a symlink, are rejected.
+func ExtractAppBackup(archivePath, dest string) error {
+	return walkAppBackup(archivePath, func(hdr *tar.Header, r io.Reader) error {
+		rel, ok := backupDataPath(hdr.Name)
+		if !ok {
+			return nil
+		}
+
+		return extractTarEntry(dest, rel, hdr, r)
+	})
+}
+
+// restoreAndroidAppData force-stops the app, clears its data directory and unpacks the backup through run-as.
+func restoreAndroidAppData(udid, pkg, archivePath string) error {
+	tmp, err := os.CreateTemp("", "sim-restore-*.tar")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary archive: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	tw := tar.NewWriter(tmp)
+	err = walkAppBackup(archivePath, func(hdr *tar.Header, r io.Reader) error {
+		rel, ok := backupDataPath(hdr.Name)
+		// lib is kept by the clear step below, and run-as cannot replace it.
+		if !ok || rel == "lib" || strings.HasPrefix(rel, "lib/") {
+			return nil
+		}
+		hdr.Name = rel
+		if hdr.Typeflag == tar.TypeDir {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := io.Copy(tw, r)
+
+		return err
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to prepare restore archive: %w", err)
+	}
+
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "am", "force-stop", pkg); err != nil {
+		return fmt.Errorf("failed to stop %s: %w", pkg, err)
+	}
+
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "push", tmp.Name(), androidRestoreTmp); err != nil {
+		return fmt.Errorf("failed to push restore archive: %w", err)
+	}
+	defer func() { _ = packageExecutor.Run(CmdAdb, "-s", udid, "shell", "rm", "-f", androidRestoreTmp) }()
+
+	// The lib entry is a system-owned symlink that run-as cannot remove.
+	clearCmd := fmt.Sprintf("run-as %s find . -mindepth 1 -maxdepth 1 ! -name lib -exec rm -rf {} +", pkg)
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", clearCmd); err != nil {
+		return fmt.Errorf("%w: %w", ErrAppNotDebuggable, err)
+	}
+
+	extractCmd := fmt.Sprintf("cat %s | run-as %s tar -xf -", androidRestoreTmp, pkg)
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", extractCmd); err != nil {
+		return fmt.Errorf("failed to restore app data on Android emulator: %w", err)
+	}
+
+	return nil
+}
+
+// writeAppBackup writes a gzipped tar archive containing the manifest followed by
+// the entries added by addData.
+func writeAppBackup(outputPath string, manifest *AppBackupManifest, addData func(tw *tar.Writer) error) (err error) {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(outputPath)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Name:    backupManifestName,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+
+	if err := addData(tw); err != nil {
+		return fmt.Errorf("failed to write backup data: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// walkAppBackup calls fn for every entry in a gzipped tar backup archive.
+func walkAppBackup(archivePath string, fn func(hdr *tar.Header, r io.Reader) error) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+		}
+		if err := fn(hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// backupDataPath returns the container-relative path of an archive entry under
+// the data directory. It rejects the directory itself and paths escaping it.
+func backupDataPath(name string) (string, bool) {
+	rel, ok := strings.CutPrefix(path.Clean(name), backupDataDir+"/")
+	if !ok || rel == "" || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+
+	return rel, true
+}
+
+// copyTarEntries copies all entries from tr into tw, placing them under prefix.
+func copyTarEntries(tw *tar.Writer, tr *tar.Reader, prefix string) error {
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "/"))
+		if name == "." || name == ".." || strings.HasPrefix(name, "../") {
+			continue
+		}
+		hdr.Name = path.Join(prefix, name)
+		if hdr.Typeflag == tar.TypeDir {
+			hdr.Name += "/"
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// addDirToTar adds the contents of root to tw, placing them under prefix.
+// Only directories, regular files and symlinks are archived.
+func addDirToTar(tw *tar.Writer, root, prefix string) error {
+	return filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		case !info.IsDir() && !info.Mode().IsRegular():
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(prefix, filepath.ToSlash(rel))
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+
+		_, err = io.Copy(tw, f)
+
+		return err
+	})
+}
+
+// extractTarEntry writes a single tar entry to dest/rel.
+func extractTarEntry(dest, rel string, hdr *tar.Header, r io.Reader) error {
+	target := filepath.Join(dest, filepath.FromSlash(rel))
+	if err := checkNoSymlinks(dest, rel); err != nil {
+		return err
+	}
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, 0o755)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, hdr.FileInfo().Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			_ = f.Close()
+
+			return err
+		}
+
+		return f.Close()
+	case tar.TypeSymlink:
+		if filepath.IsAbs(hdr.Linkname) || !isWithinDir(dest, filepath.Join(filepath.Dir(target), hdr.Linkname)) {
+			return fmt.Errorf("%w: symlink %s points outside the container: %s", ErrInvalidBackup, rel, hdr.Linkname)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+
+		return os.Symlink(hdr.Linkname, target)
+	default:
+		return nil
+	}
+}
+
+// checkNoSymlinks fails if dest/rel or any directory between it and dest is a symlink, so
+// an entry can never be written through a link created by an earlier entry.
+func checkNoSymlinks(dest, rel string) error {
+	p := dest
+	for part := range strings.SplitSeq(rel, "/") {
+		p = filepath.Join(p, part)
+
+		info, err := os.Lstat(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s is written through a symlink", ErrInvalidBackup, rel)
+		}
+	}
+
+	return nil
+}
+
+// isWithinDir reports whether p is dir or lies inside it.
+func isWithinDir(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
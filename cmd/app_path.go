@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// appPathPrefix marks a copy path inside an iOS app container.
+	appPathPrefix = "app:"
+	// defaultAppContainer is used when an app: path names no container.
+	defaultAppContainer = "data"
+)
+
+// AppPath addresses a file inside an installed app's container, written as
+// app:<bundle-id>[:<container>]/<path>. Container is "data" (the default, holding
+// Documents, Library and tmp), "app" (the read-only bundle) or an app group ID (group.*).
+type AppPath struct {
+	BundleID  string
+	Container string
+	Path      string // Relative to the container root; a trailing "/" marks a directory.
+}
+
+// String formats p in the app:<bundle-id>[:<container>]/<path> form.
+func (p AppPath) String() string {
+	s := appPathPrefix + p.BundleID
+	if p.Container != defaultAppContainer {
+		s += ":" + p.Container
+	}
+
+	return s + "/" + p.Path
+}
+
+// IsAppPath reports whether s uses the app: prefix.
+func IsAppPath(s string) bool {
+	return strings.HasPrefix(s, appPathPrefix)
+}
+
+// ParseAppPath parses app:<bundle-id>[:<container>]/<path>.
+func ParseAppPath(s string) (AppPath, error) {
+	rest, ok := strings.CutPrefix(s, appPathPrefix)
+	if !ok {
+		return AppPath{}, fmt.Errorf("%w: %q does not start with %s", ErrInvalidAppPath, s, appPathPrefix)
+	}
+
+	target, rel, _ := strings.Cut(rest, "/")
+	bundleID, container, hasContainer := strings.Cut(target, ":")
+	if !hasContainer || container == "" {
+		container = defaultAppContainer
+	}
+
+	if err := validateAppID(bundleID); err != nil {
+		return AppPath{}, fmt.Errorf("%w: %w", ErrInvalidAppPath, err)
+	}
+	if container != "app" && container != "data" && !strings.HasPrefix(container, "group.") {
+		return AppPath{}, fmt.Errorf("%w: unknown container %q (use data, app or a group.* identifier)", ErrInvalidAppPath, container)
+	}
+
+	// Clean as an absolute path so ".." cannot climb out of the container, keeping a
+	// trailing slash that marks a directory destination.
+	dir := strings.HasSuffix(rel, "/")
+	rel = strings.TrimPrefix(path.Clean("/"+rel), "/")
+	if dir && rel != "" {
+		rel += "/"
+	}
+
+	return AppPath{BundleID: bundleID, Container: container, Path: rel}, nil
+}
+
+// ResolveAppPath returns the host path of p on the iOS simulator udid, using
+// 'simctl get_app_container'.
+func ResolveAppPath(udid string, p AppPath) (string, error) {
+	container, err := iosAppContainer(udid, p.BundleID, p.Container)
+	if err != nil {
+		return "", err
+	}
+
+	resolved := filepath.Join(container, filepath.FromSlash(p.Path))
+	if strings.HasSuffix(p.Path, "/") {
+		resolved += string(filepath.Separator)
+	}
+
+	return resolved, nil
+}
+
+// writeHostFile copies the host file src to dst, creating parent directories and
+// reporting the bytes written so far to report.
+func writeHostFile(src, dst string, report func(int64)) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(&progressWriter{w: out, report: report}, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	appearanceLight = "light"
+	appearanceDark  = "dark"
+	settingReset    = "reset"
+	// defaultTextSize is the content size category both platforms use by default.
+	defaultTextSize = "large"
+)
+
+// textSizeScales maps iOS content size categories to the equivalent Android font_scale.
+var textSizeScales = map[string]float64{
+	"extra-small":                           0.8,
+	"small":                                 0.85,
+	"medium":                                0.93,
+	"large":                                 1.0,
+	"extra-large":                           1.15,
+	"extra-extra-large":                     1.3,
+	"extra-extra-extra-large":               1.5,
+	"accessibility-medium":                  1.6,
+	"accessibility-large":                   1.7,
+	"accessibility-extra-large":             1.8,
+	"accessibility-extra-extra-large":       1.9,
+	"accessibility-extra-extra-extra-large": 2.0,
+}
+
+// displaySizePattern matches WxH display sizes such as 1080x2400.
+var displaySizePattern = regexp.MustCompile(`^\d+x\d+$`)
+
+// DisplaySettings holds the current appearance and display values of a device.
+// Values the platform cannot report are left empty.
+type DisplaySettings struct {
+	Appearance string
+	TextSize   string
+	Size       string
+	Density    string
+}
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Change appearance and display settings",
+	Long: `Switch dark mode, text size and display size/density on a running device.
+
+iOS simulators use 'simctl ui'. Android emulators use 'cmd uimode', the
+font_scale system setting and 'wm size'/'wm density'.
+
+Pass 'reset' to any setting to restore its default. The current values are shown by 'sim info'.`,
+}
+
+var uiAppearanceCmd = &cobra.Command{
+	Use:               "appearance [device-name-or-udid] <light|dark|reset>",
+	Short:             "Switch between light and dark mode",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, value := splitDeviceArg(args)
+
+		return SetAppearance(deviceID, value)
+	},
+}
+
+var uiTextSizeCmd = &cobra.Command{
+	Use:   "text-size [device-name-or-udid] <category|reset>",
+	Short: "Change the preferred text size",
+	Long: `Change the preferred text size (Dynamic Type on iOS, font scale on Android).
+
+Categories: ` + strings.Join(TextSizeCategories(), ", ") + `
+
+On Android a numeric font scale such as 1.3 is also accepted.`,
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, value := splitDeviceArg(args)
+
+		return SetTextSize(deviceID, value)
+	},
+}
+
+var uiDisplayCmd = &cobra.Command{
+	Use:               "display [device-name-or-udid]",
+	Short:             "Override the display size and density (Android only)",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		size, _ := cmd.Flags().GetString("size")
+		density, _ := cmd.Flags().GetInt("density")
+		if reset, _ := cmd.Flags().GetBool(settingReset); reset {
+			size = settingReset
+			density = -1
+		}
+
+		return SetDisplay(deviceID, size, density)
+	},
+}
+
+var uiResetCmd = &cobra.Command{
+	Use:               "reset [device-name-or-udid]",
+	Short:             "Reset appearance, text size and display settings",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		return ResetDisplaySettings(deviceID)
+	},
+}
+
+func init() {
+	uiDisplayCmd.Flags().String("size", "", "Display size in pixels (WxH)")
+	uiDisplayCmd.Flags().Int("density", 0, "Display density in dpi")
+	uiDisplayCmd.Flags().Bool(settingReset, false, "Restore the physical size and density")
+
+	uiCmd.AddCommand(uiAppearanceCmd)
+	uiCmd.AddCommand(uiTextSizeCmd)
+	uiCmd.AddCommand(uiDisplayCmd)
+	uiCmd.AddCommand(uiResetCmd)
+}
+
+// splitDeviceArg resolves [device] <value> positional arguments.
+func splitDeviceArg(args []string) (deviceID, value string) {
+	if len(args) == 1 {
+		return "", args[0]
+	}
+
+	return args[0], args[1]
+}
+
+// TextSizeCategories returns the supported text size categories, smallest first.
+func TextSizeCategories() []string {
+	names := make([]string, 0, len(textSizeScales))
+	for name := range textSizeScales {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return textSizeScales[names[i]] < textSizeScales[names[j]] })
+
+	return names
+}
+
+// findSettingsDevice resolves a running device and rejects iOS on non-macOS hosts.
+func findSettingsDevice(deviceID string) (udid, name string, isAndroid bool, err error) {
+	udid, name, isAndroid, err = FindRunningDevice(deviceID)
+	if err != nil {
+		return "", "", false, err
+	}
+	if !isAndroid && runtime.GOOS != DarwinOS {
+		return "", "", false, ErrIOSMacOnly
+	}
+
+	return udid, name, isAndroid, nil
+}
+
+// SetAppearance switches the device to light or dark mode. "reset" restores light mode.
+func SetAppearance(deviceID, mode string) error {
+	mode = strings.ToLower(mode)
+	if mode == settingReset {
+		mode = appearanceLight
+	}
+	if mode != appearanceLight && mode != appearanceDark {
+		return fmt.Errorf("%w: appearance must be light, dark or reset, got %q", ErrInvalidSetting, mode)
+	}
+
+	udid, name, isAndroid, err := findSettingsDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if err := applyAppearance(udid, isAndroid, mode); err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Appearance of '%s' set to %s.", name, mode))
+
+	return nil
+}
+
+// SetTextSize changes the preferred text size. "reset" restores the default category.
+func SetTextSize(deviceID, category string) error {
+	category = strings.ToLower(category)
+	if category == settingReset {
+		category = defaultTextSize
+	}
+
+	udid, name, isAndroid, err := findSettingsDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if err := applyTextSize(udid, isAndroid, category); err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Text size of '%s' set to %s.", name, category))
+
+	return nil
+}
+
+// SetDisplay overrides the display size (WxH) and/or density (dpi) of an Android emulator.
+// Pass "reset" as size and a negative density to restore the physical values; an empty size
+// or zero density leaves that value unchanged.
+func SetDisplay(deviceID, size string, density int) error {
+	if size == "" && density == 0 {
+		return fmt.Errorf("%w: specify --size, --density or --reset", ErrInvalidSetting)
+	}
+	if size != "" && size != settingReset && !displaySizePattern.MatchString(size) {
+		return fmt.Errorf("%w: size must be WxH, got %q", ErrInvalidSetting, size)
+	}
+
+	udid, name, isAndroid, err := findSettingsDevice(deviceID)
+	if err != nil {
+		return err
+	}
+	if !isAndroid {
+		return fmt.Errorf("%w: display size and density overrides on iOS simulators", ErrUnsupportedPlatform)
+	}
+
+	if size != "" {
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "wm", "size", size); err != nil {
+			return fmt.Errorf("failed to set display size: %w", err)
+		}
+	}
+
+	switch {
+	case density < 0:
+		err = packageExecutor.Run(CmdAdb, "-s", udid, "shell", "wm", "density", settingReset)
+	case density > 0:
+		err = packageExecutor.Run(CmdAdb, "-s", udid, "shell", "wm", "density", strconv.Itoa(density))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set display density: %w", err)
+	}
+
+	PrintSuccess(fmt.Sprintf("Display settings of '%s' updated.", name))
+
+	return nil
+}
+
+// ResetDisplaySettings restores the default appearance, text size and display settings.
+func ResetDisplaySettings(deviceID string) error {
+	udid, name, isAndroid, err := findSettingsDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	err = RunSpinner(fmt.Sprintf("Resetting display settings on '%s'...", name), func() error {
+		if err := applyAppearance(udid, isAndroid, appearanceLight); err != nil {
+			return err
+		}
+		if err := applyTextSize(udid, isAndroid, defaultTextSize); err != nil {
+			return err
+		}
+		if !isAndroid {
+			return nil
+		}
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "wm", "size", settingReset); err != nil {
+			return fmt.Errorf("failed to reset display size: %w", err)
+		}
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "wm", "density", settingReset); err != nil {
+			return fmt.Errorf("failed to reset display density: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Display settings of '%s' reset to defaults.", name))
+
+	return nil
+}
+
+func applyAppearance(udid string, isAndroid bool, mode string) error {
+	if !isAndroid {
+		if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "ui", udid, "appearance", mode); err != nil {
+			return fmt.Errorf("failed to set iOS appearance: %w", err)
+		}
+
+		return nil
+	}
+
+	night := "no"
+	if mode == appearanceDark {
+		night = "yes"
+	}
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "cmd", "uimode", "night", night); err != nil {
+		return fmt.Errorf("failed to set Android night mode: %w", err)
+	}
+
+	return nil
+}
+
+func applyTextSize(udid string, isAndroid bool, category string) error {
+	scale, known := textSizeScales[category]
+
+	if !isAndroid {
+		if !known {
+			return fmt.Errorf("%w: unknown text size %q (supported: %s)", ErrInvalidSetting, category,
+				strings.Join(TextSizeCategories(), ", "))
+		}
+		if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "ui", udid, "content_size", category); err != nil {
+			return fmt.Errorf("failed to set iOS content size: %w", err)
+		}
+
+		return nil
+	}
+
+	if !known {
+		v, err := strconv.ParseFloat(category, 64)
+		if err != nil || v <= 0 {
+			return fmt.Errorf("%w: unknown text size %q (use a category or a font scale such as 1.3)", ErrInvalidSetting, category)
+		}
+		scale = v
+	}
+
+	value := strconv.FormatFloat(scale, 'f', -1, 64)
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "settings", "put", "system", "font_scale", value); err != nil {
+		return fmt.Errorf("failed to set Android font scale: %w", err)
+	}
+
+	return nil
+}
+
+// GetDisplaySettings reads the current appearance and display values from a running device.
+func GetDisplaySettings(udid string, isAndroid bool) DisplaySettings {
+	var s DisplaySettings
+
+	if !isAndroid {
+		if out, err := packageExecutor.Output(CmdXCrun, CmdSimctl, "ui", udid, "appearance"); err == nil {
+			s.Appearance = strings.TrimSpace(string(out))
+		}
+		if out, err := packageExecutor.Output(CmdXCrun, CmdSimctl, "ui", udid, "content_size"); err == nil {
+			s.TextSize = strings.TrimSpace(string(out))
+		}
+
+		return s
+	}
+
+	adbShell := func(args ...string) string {
+		out, err := packageExecutor.Output(CmdAdb, append([]string{"-s", udid, "shell"}, args...)...)
+		if err != nil {
+			return ""
+		}
+
+		return strings.TrimSpace(string(out))
+	}
+
+	// "Night mode: yes"
+	if night := adbShell("cmd", "uimode", "night"); night != "" {
+		s.Appearance = appearanceLight
+		if strings.HasSuffix(night, "yes") {
+			s.Appearance = appearanceDark
+		}
+	}
+
+	s.TextSize = adbShell("settings", "get", "system", "font_scale")
+	if s.TextSize == "null" {
+		s.TextSize = "1.0"
+	}
+	s.Size = lastWMValue(adbShell("wm", "size"))
+	s.Density = lastWMValue(adbShell("wm", "density"))
+
+	return s
+}
+
+// lastWMValue returns the effective value from 'wm size'/'wm density' output, where an
+// "Override ..." line follows the "Physical ..." line when an override is active.
+func lastWMValue(out string) string {
+	var value string
+	for line := range strings.SplitSeq(out, "\n") {
+		if _, v, ok := strings.Cut(line, ":"); ok {
+			value = strings.TrimSpace(v)
+		}
+	}
+
+	return value
+}
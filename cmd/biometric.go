@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// iosEnrollmentNotification toggles biometric enrollment on iOS simulators.
+	iosEnrollmentNotification = "com.apple.BiometricKit.enrollmentChanged"
+	// defaultFingerID is the emulator finger used for enrollment and matches.
+	defaultFingerID = 1
+	// unenrolledFingerID is a finger that is never enrolled, so touching it fails authentication.
+	unenrolledFingerID = 99
+)
+
+// iosBiometricSensors are the simulated sensors notified by match and fail:
+// fingerTouch is Touch ID and pearl is Face ID. Only the one the device has reacts.
+var iosBiometricSensors = []string{"fingerTouch", "pearl"}
+
+var biometricCmd = &cobra.Command{
+	Use:     "biometric",
+	Aliases: []string{"bio"},
+	Short:   "Simulate Face ID, Touch ID and fingerprint events",
+	Long: `Enroll biometrics and simulate matching or non-matching attempts.
+
+iOS simulators receive BiometricKit notifications through 'simctl spawn notifyutil'.
+Android emulators receive 'adb emu finger touch' events; enrolling a fingerprint needs
+a screen lock, which 'sim biometric enroll --pin' can set up.`,
+}
+
+var biometricEnrollCmd = &cobra.Command{
+	Use:               "enroll [device-name-or-udid]",
+	Short:             "Enroll Face ID / Touch ID or a fingerprint",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+		pin, _ := cmd.Flags().GetString("pin")
+		finger, _ := cmd.Flags().GetInt("finger")
+
+		return EnrollBiometric(deviceID, pin, finger, os.Stdin)
+	},
+}
+
+var biometricUnenrollCmd = &cobra.Command{
+	Use:               "unenroll [device-name-or-udid]",
+	Short:             "Remove Face ID / Touch ID enrollment (iOS only)",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		udid, name, isAndroid, err := FindRunningDevice(deviceID)
+		if err != nil {
+			return err
+		}
+		if isAndroid {
+			return fmt.Errorf("%w: remove Android fingerprints from Settings > Security", ErrUnsupportedPlatform)
+		}
+		if err := setIOSEnrollment(udid, false); err != nil {
+			return err
+		}
+
+		PrintSuccess(fmt.Sprintf("Biometric enrollment removed from '%s'.", name))
+
+		return nil
+	},
+}
+
+var biometricMatchCmd = &cobra.Command{
+	Use:               "match [device-name-or-udid]",
+	Short:             "Simulate a matching face or finger",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+		finger, _ := cmd.Flags().GetInt("finger")
+
+		return SendBiometric(deviceID, true, finger)
+	},
+}
+
+var biometricFailCmd = &cobra.Command{
+	Use:               "fail [device-name-or-udid]",
+	Short:             "Simulate a non-matching face or finger",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		return SendBiometric(deviceID, false, unenrolledFingerID)
+	},
+}
+
+func init() {
+	biometricEnrollCmd.Flags().String("pin", "", "Android: set this PIN as the screen lock before enrolling")
+	biometricEnrollCmd.Flags().Int("finger", defaultFingerID, "Android: emulator finger ID to enroll")
+	biometricMatchCmd.Flags().Int("finger", defaultFingerID, "Android: emulator finger ID to touch")
+
+	biometricCmd.AddCommand(biometricEnrollCmd)
+	biometricCmd.AddCommand(biometricUnenrollCmd)
+	biometricCmd.AddCommand(biometricMatchCmd)
+	biometricCmd.AddCommand(biometricFailCmd)
+}
+
+// EnrollBiometric enrolls biometrics on the device. On Android it optionally sets pin as
+// the screen lock, opens the fingerprint enrollment wizard and sends a finger touch each
+// time a line is read from in, until "q" or EOF.
+func EnrollBiometric(deviceID, pin string, finger int, in io.Reader) error {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if !isAndroid {
+		if err := setIOSEnrollment(udid, true); err != nil {
+			return err
+		}
+
+		PrintSuccess(fmt.Sprintf("Face ID / Touch ID enrolled on '%s'.", name))
+
+		return nil
+	}
+
+	if pin != "" {
+		if _, err := strconv.Atoi(pin); err != nil || len(pin) < 4 {
+			return fmt.Errorf("%w: PIN must be at least 4 digits", ErrInvalidSetting)
+		}
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "locksettings", "set-pin", pin); err != nil {
+			return fmt.Errorf("failed to set screen lock PIN (is a lock already set?): %w", err)
+		}
+		PrintInfo(fmt.Sprintf("Screen lock PIN set to %s.", pin))
+	} else {
+		PrintInfo("Fingerprint enrollment requires a screen lock. If none is set, re-run with --pin 1234.")
+	}
+
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "am", "start", "-a", "android.settings.FINGERPRINT_ENROLL"); err != nil {
+		return fmt.Errorf("failed to open fingerprint enrollment: %w", err)
+	}
+
+	PrintInfo("Follow the wizard on the emulator (confirm the screen lock when asked).")
+	PrintInfo("Press Enter each time it asks you to touch the sensor; type q and Enter when enrollment is complete.")
+
+	touches := 0
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if strings.EqualFold(strings.TrimSpace(scanner.Text()), "q") {
+			break
+		}
+		if err := touchAndroidFinger(udid, finger); err != nil {
+			return err
+		}
+		touches++
+		PrintInfo(fmt.Sprintf("Touched finger %d (%d).", finger, touches))
+	}
+
+	PrintSuccess(fmt.Sprintf("Sent %d touches for finger %d on '%s'. Use 'sim biometric match' to authenticate.", touches, finger, name))
+
+	return nil
+}
+
+// SendBiometric simulates a matching or non-matching biometric attempt. finger is the
+// Android emulator finger touched; it is ignored on iOS.
+func SendBiometric(deviceID string, match bool, finger int) error {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	result := "match"
+	if !match {
+		result = "nomatch"
+	}
+
+	if isAndroid {
+		if err := touchAndroidFinger(udid, finger); err != nil {
+			return err
+		}
+	} else {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+		for _, sensor := range iosBiometricSensors {
+			notification := fmt.Sprintf("com.apple.BiometricKit_Sim.%s.%s", sensor, result)
+			if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "spawn", udid, "notifyutil", "-p", notification); err != nil {
+				return fmt.Errorf("failed to post %s: %w", notification, err)
+			}
+		}
+	}
+
+	PrintSuccess(fmt.Sprintf("Sent a biometric %s to '%s'.", result, name))
+
+	return nil
+}
+
+// setIOSEnrollment sets the simulated enrollment state and notifies BiometricKit.
+func setIOSEnrollment(udid string, enrolled bool) error {
+	if runtime.GOOS != DarwinOS {
+		return ErrIOSMacOnly
+	}
+
+	state := "0"
+	if enrolled {
+		state = "1"
+	}
+
+	if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "spawn", udid, "notifyutil", "-s", iosEnrollmentNotification, state); err != nil {
+		return fmt.Errorf("failed to set biometric enrollment: %w", err)
+	}
+	if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "spawn", udid, "notifyutil", "-p", iosEnrollmentNotification); err != nil {
+		return fmt.Errorf("failed to post enrollment change: %w", err)
+	}
+
+	return nil
+}
+
+func touchAndroidFinger(udid string, finger int) error {
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "emu", "finger", "touch", strconv.Itoa(finger)); err != nil {
+		return fmt.Errorf("failed to touch fingerprint sensor: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"crypto/md5" //nolint:gosec // OpenSSL's subject_hash_old is defined in terms of MD5.
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	androidUserCertDir   = "/data/misc/user/0/cacerts-added"
+	androidSystemCertDir = "/system/etc/security/cacerts"
+	androidDownloadDir   = "/sdcard/Download"
+)
+
+var certCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Install trusted CA certificates for HTTPS debugging",
+	Long: `Install or remove trusted root certificates, e.g. a Charles or mitmproxy CA.
+
+iOS simulators use 'simctl keychain'. Android emulators get the certificate in the
+user store when 'adb root' is available (Google APIs images), or in the system store
+with --system, which additionally needs an emulator started with -writable-system.
+On other images the certificate is copied to the Download folder for manual install.`,
+}
+
+var certAddCmd = &cobra.Command{
+	Use:               "add [device-name-or-udid] <cert.pem|cert.der>",
+	Short:             "Trust a CA certificate",
+	ValidArgsFunction: validDeviceAndFileArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, certPath := splitDeviceArg(args)
+		system, _ := cmd.Flags().GetBool("system")
+
+		return AddCertificate(deviceID, certPath, system)
+	},
+}
+
+var certResetCmd = &cobra.Command{
+	Use:               "reset [device-name-or-udid]",
+	Short:             "Remove user-installed CA certificates",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		return ResetCertificates(deviceID)
+	},
+}
+
+func init() {
+	certAddCmd.Flags().Bool("system", false, "Install into the Android system store (needs -writable-system)")
+
+	certCmd.AddCommand(certAddCmd)
+	certCmd.AddCommand(certResetCmd)
+}
+
+// ParseCertificate decodes the first certificate in PEM or DER encoded data.
+func ParseCertificate(data []byte) (*x509.Certificate, error) {
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidCertificate, err)
+			}
+
+			return cert, nil
+		}
+	}
+
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a PEM or DER certificate", ErrInvalidCertificate)
+	}
+
+	return cert, nil
+}
+
+// AndroidCertFilename returns the name Android expects for a CA in its certificate
+// stores: OpenSSL's subject_hash_old (the first four bytes of the MD5 of the DER
+// subject, little-endian) followed by ".0".
+func AndroidCertFilename(cert *x509.Certificate) string {
+	sum := md5.Sum(cert.RawSubject) //nolint:gosec // See import comment.
+
+	return fmt.Sprintf("%08x.0", binary.LittleEndian.Uint32(sum[:4]))
+}
+
+// AddCertificate trusts the CA certificate at certPath on the device.
+// Pass an empty deviceID to use the first running device.
+func AddCertificate(deviceID, certPath string, system bool) error {
+	data, err := os.ReadFile(certPath)
+	if err != nil {
+		return fmt.Errorf("failed to read certificate: %w", err)
+	}
+
+	cert, err := ParseCertificate(data)
+	if err != nil {
+		return err
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	subject := cert.Subject.CommonName
+	if subject == "" {
+		subject = cert.Subject.String()
+	}
+
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+		if system {
+			PrintInfo("--system only applies to Android; iOS root certificates are always trusted system-wide.")
+		}
+
+		absPath, err := filepath.Abs(certPath)
+		if err != nil {
+			return fmt.Errorf("invalid certificate path: %w", err)
+		}
+		if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "keychain", udid, "add-root-cert", absPath); err != nil {
+			return fmt.Errorf("failed to add root certificate to iOS simulator: %w", err)
+		}
+
+		PrintSuccess(fmt.Sprintf("Trusted '%s' on '%s'.", subject, name))
+
+		return nil
+	}
+
+	// Android expects PEM regardless of the input encoding.
+	local, err := os.CreateTemp("", "sim-cert-*.pem")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary certificate: %w", err)
+	}
+	defer func() { _ = os.Remove(local.Name()) }()
+
+	if err := pem.Encode(local, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+		_ = local.Close()
+		return fmt.Errorf("failed to write temporary certificate: %w", err)
+	}
+	if err := local.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary certificate: %w", err)
+	}
+
+	filename := AndroidCertFilename(cert)
+
+	if !androidRoot(udid) {
+		if system {
+			return fmt.Errorf("%w: 'adb root' is not available; use a Google APIs system image", ErrCertStoreUnavailable)
+		}
+
+		remote := androidDownloadDir + "/" + strings.TrimSuffix(filename, ".0") + ".crt"
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "push", local.Name(), remote); err != nil {
+			return fmt.Errorf("failed to copy certificate to Android: %w", err)
+		}
+
+		PrintInfo(fmt.Sprintf("This image does not allow 'adb root', so the certificate was copied to %s.", remote))
+		PrintInfo("Install it from Settings > Security > Encryption & credentials > Install a certificate > CA certificate.")
+
+		return nil
+	}
+
+	dir := androidUserCertDir
+	if system {
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "remount"); err != nil {
+			return fmt.Errorf("%w: 'adb remount' failed; start the emulator with -writable-system: %w", ErrCertStoreUnavailable, err)
+		}
+		dir = androidSystemCertDir
+	}
+
+	remote := dir + "/" + filename
+	steps := [][]string{
+		{"shell", "mkdir", "-p", dir},
+		{"push", local.Name(), remote},
+		{"shell", "chmod", "644", remote},
+	}
+	if !system {
+		steps = append(steps, []string{"shell", "chown", "system:system", remote})
+	}
+
+	for _, step := range steps {
+		if err := packageExecutor.Run(CmdAdb, append([]string{"-s", udid}, step...)...); err != nil {
+			return fmt.Errorf("failed to install certificate on Android: %w", err)
+		}
+	}
+
+	PrintSuccess(fmt.Sprintf("Trusted '%s' on '%s' (%s).", subject, name, remote))
+	if system {
+		PrintInfo("Restart the emulator with 'sim restart' for all apps to pick up the new system certificate.")
+	}
+
+	return nil
+}
+
+// ResetCertificates removes CA certificates that were added to the device.
+// On Android only the user store can be reset; system store changes need a wipe.
+func ResetCertificates(deviceID string) error {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+		if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "keychain", udid, "reset"); err != nil {
+			return fmt.Errorf("failed to reset iOS simulator keychain: %w", err)
+		}
+
+		PrintSuccess(fmt.Sprintf("Keychain reset on '%s'.", name))
+
+		return nil
+	}
+
+	if !androidRoot(udid) {
+		return fmt.Errorf("%w: 'adb root' is not available; remove certificates from Settings > Security", ErrCertStoreUnavailable)
+	}
+
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "rm", "-rf", androidUserCertDir); err != nil {
+		return fmt.Errorf("failed to remove user certificates: %w", err)
+	}
+
+	PrintSuccess(fmt.Sprintf("User CA certificates removed from '%s'.", name))
+
+	return nil
+}
+
+// androidRoot restarts adbd as root and reports whether the shell now runs as uid 0.
+// 'adb root' exits successfully on production builds too, so the uid is checked.
+func androidRoot(udid string) bool {
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "root"); err != nil {
+		return false
+	}
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "wait-for-device"); err != nil {
+		return false
+	}
+
+	out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "id", "-u")
+
+	return err == nil && strings.TrimSpace(string(out)) == "0"
+}
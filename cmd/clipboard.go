@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/spf13/cobra"
+)
+
+// clipboardPollInterval is how often --watch compares the host and device clipboards.
+const clipboardPollInterval = time.Second
+
+var clipboardCmd = &cobra.Command{
+	Use:     "clipboard [device-name-or-udid]",
+	Aliases: []string{"cb", "pb"},
+	Short:   "Move text between the host and device clipboards",
+	Long: `Copy text to or from a device clipboard, or keep it in sync with the host.
+
+iOS simulators use 'simctl pbcopy/pbpaste'. Android emulators use 'cmd clipboard' when
+the system image provides it; otherwise 'push' types the text into the focused field
+with 'input text' and 'pull' is unavailable.
+
+Run 'sim clipboard --watch' to keep the host clipboard and one device in sync. Watching
+needs 'cmd clipboard' on Android; host clipboard changes are never typed into the device.`,
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watch, _ := cmd.Flags().GetBool("watch"); !watch {
+			return cmd.Help()
+		}
+
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigChan)
+
+		go func() {
+			<-sigChan
+			cancel()
+		}()
+
+		return WatchClipboard(ctx, deviceID)
+	},
+}
+
+var clipboardPushCmd = &cobra.Command{
+	Use:               "push [device-name-or-udid] [text]",
+	Short:             "Copy text (or the host clipboard) to the device clipboard",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID, text string
+
+		fromHost, _ := cmd.Flags().GetBool("from-host")
+		switch {
+		case fromHost && len(args) > 1:
+			return fmt.Errorf("%w: text cannot be combined with --from-host", ErrInvalidSetting)
+		case fromHost || len(args) == 0:
+			if len(args) == 1 {
+				deviceID = args[0]
+			}
+			hostText, err := clipboard.ReadAll()
+			if err != nil {
+				return fmt.Errorf("failed to read host clipboard: %w", err)
+			}
+			text = hostText
+		case len(args) == 1:
+			text = args[0]
+		default:
+			deviceID, text = args[0], args[1]
+		}
+
+		return PushClipboard(deviceID, text)
+	},
+}
+
+var clipboardPullCmd = &cobra.Command{
+	Use:               "pull [device-name-or-udid]",
+	Short:             "Print the device clipboard (or copy it to the host)",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		text, err := PullClipboard(deviceID)
+		if err != nil {
+			return err
+		}
+
+		if toHost, _ := cmd.Flags().GetBool("to-host"); toHost {
+			if err := copyToClipboard(text); err != nil {
+				return fmt.Errorf("failed to write host clipboard: %w", err)
+			}
+			PrintSuccess("Device clipboard copied to the host clipboard.")
+
+			return nil
+		}
+
+		fmt.Println(text)
+
+		return nil
+	},
+}
+
+func init() {
+	clipboardCmd.Flags().Bool("watch", false, "Keep the host and device clipboards in sync until Ctrl+C")
+	clipboardPushCmd.Flags().Bool("from-host", false, "Send the current host clipboard")
+	clipboardPullCmd.Flags().Bool("to-host", false, "Copy the device clipboard to the host clipboard")
+
+	clipboardCmd.AddCommand(clipboardPushCmd)
+	clipboardCmd.AddCommand(clipboardPullCmd)
+}
+
+// PushClipboard sets the device clipboard to text. Pass an empty deviceID to use the first running device.
+func PushClipboard(deviceID, text string) error {
+	udid, name, isAndroid, err := findSettingsDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	err = setDeviceClipboard(udid, isAndroid, text)
+	if isAndroid && errors.Is(err, ErrUnsupportedPlatform) {
+		// 'input text' treats %s as a space and cannot type spaces literally.
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "input", "text", shellQuote(androidInputText(text))); err != nil {
+			return fmt.Errorf("failed to type text on Android emulator: %w", err)
+		}
+		PrintInfo(fmt.Sprintf("'cmd clipboard' is not available on '%s'; the text was typed into the focused field instead.", name))
+
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Copied %d characters to the clipboard of '%s'.", len([]rune(text)), name))
+
+	return nil
+}
+
+// PullClipboard returns the text on the device clipboard. Pass an empty deviceID to use the first running device.
+func PullClipboard(deviceID string) (string, error) {
+	udid, _, isAndroid, err := findSettingsDevice(deviceID)
+	if err != nil {
+		return "", err
+	}
+
+	return readDeviceClipboard(udid, isAndroid)
+}
+
+// WatchClipboard mirrors clipboard changes between the host and a device until ctx is cancelled.
+// When both sides change between polls, the host wins. Android images without 'cmd clipboard'
+// return ErrUnsupportedPlatform rather than typing every host clipboard change into the device.
+func WatchClipboard(ctx context.Context, deviceID string) error {
+	udid, name, isAndroid, err := findSettingsDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	// Reading first checks that the device clipboard is reachable in both directions.
+	if _, err := readDeviceClipboard(udid, isAndroid); err != nil {
+		return err
+	}
+
+	last, err := clipboard.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read host clipboard: %w", err)
+	}
+	if err := setDeviceClipboard(udid, isAndroid, last); err != nil {
+		return err
+	}
+
+	PrintInfo(fmt.Sprintf("Syncing the clipboard with '%s' (Press Ctrl+C to stop)...", name))
+
+	ticker := time.NewTicker(clipboardPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			PrintSuccess("Clipboard sync stopped.")
+			return nil
+		case <-ticker.C:
+		}
+
+		host, err := clipboard.ReadAll()
+		if err != nil {
+			return fmt.Errorf("failed to read host clipboard: %w", err)
+		}
+		if host != last {
+			if err := setDeviceClipboard(udid, isAndroid, host); err != nil {
+				return err
+			}
+			last = host
+			PrintInfo(fmt.Sprintf("[%s] host → %s", time.Now().Format("15:04:05"), name))
+
+			continue
+		}
+
+		device, err := readDeviceClipboard(udid, isAndroid)
+		if err != nil {
+			return err
+		}
+		if device != last {
+			if err := copyToClipboard(device); err != nil {
+				return fmt.Errorf("failed to write host clipboard: %w", err)
+			}
+			last = device
+			PrintInfo(fmt.Sprintf("[%s] %s → host", time.Now().Format("15:04:05"), name))
+		}
+	}
+}
+
+// setDeviceClipboard sets the device clipboard. Android images without 'cmd clipboard'
+// return ErrUnsupportedPlatform; other failures are returned as they are.
+func setDeviceClipboard(udid string, isAndroid bool, text string) error {
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+
+		// simctl pbcopy reads the text from stdin.
+		pbcopy := exec.Command(CmdXCrun, CmdSimctl, "pbcopy", udid)
+		pbcopy.Stdin = strings.NewReader(text)
+		if output, err := pbcopy.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to set iOS simulator clipboard: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
+		}
+
+		return nil
+	}
+
+	err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "cmd", "clipboard", "set-primary-clip", shellQuote(text))
+	if androidCommandMissing(nil, err) {
+		return fmt.Errorf("%w: setting the clipboard needs 'cmd clipboard', which this Android image does not provide",
+			ErrUnsupportedPlatform)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set Android emulator clipboard: %w", err)
+	}
+
+	return nil
+}
+
+// readDeviceClipboard returns the text on the device clipboard.
+func readDeviceClipboard(udid string, isAndroid bool) (string, error) {
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return "", ErrIOSMacOnly
+		}
+
+		out, err := packageExecutor.Output(CmdXCrun, CmdSimctl, "pbpaste", udid)
+		if err != nil {
+			return "", fmt.Errorf("failed to read iOS simulator clipboard: %w", err)
+		}
+
+		return string(out), nil
+	}
+
+	out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "cmd", "clipboard", "get-primary-clip")
+	if androidCommandMissing(out, err) {
+		return "", fmt.Errorf("%w: reading the clipboard needs 'cmd clipboard', which this Android image does not provide",
+			ErrUnsupportedPlatform)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read Android emulator clipboard: %w", err)
+	}
+
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// androidCommandMissing reports whether the output of an 'adb shell cmd' call shows that the
+// image does not implement the command, as opposed to the command failing.
+func androidCommandMissing(out []byte, err error) bool {
+	text := string(out)
+	if err != nil {
+		text += err.Error()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		text += string(exitErr.Stderr)
+	}
+
+	return strings.Contains(text, "No shell command implementation") || strings.Contains(text, "Unknown command")
+}
+
+// androidInputText escapes text for 'adb shell input text', which reads %s as a space.
+func androidInputText(text string) string {
+	return strings.ReplaceAll(text, " ", "%s")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -109,7 +110,27 @@ func validDeviceArgs(cmd *cobra.Command, args []string, toComplete string) ([]st
 }
 
 // validDeviceAndFileArgs is a ValidArgsFunction for commands that take a device name and an optional file.
+// Arguments starting with '@' complete to saved deeplink names, and the arguments after
+// one complete to its key= parameters.
 func validDeviceAndFileArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if strings.HasPrefix(toComplete, deeplinkPrefix) {
+		names := DeeplinkNames()
+		for i := range names {
+			names[i] = deeplinkPrefix + names[i]
+		}
+
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, arg := range args {
+		if name, ok := strings.CutPrefix(arg, deeplinkPrefix); ok {
+			var params []string
+			for _, param := range DeeplinkParams(Deeplinks()[name]) {
+				params = append(params, param+"=")
+			}
+
+			return params, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+		}
+	}
 	if len(args) == 0 {
 		// First arg: device name
 		names := getDeviceNames()
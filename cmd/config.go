@@ -9,12 +9,18 @@ import (
 
 // Config holds the application's persistent configuration.
 type Config struct {
-	LastStartedDevice *Device `json:"lastStartedDevice,omitempty"`
-	DefaultDevice     string  `json:"defaultDevice,omitempty"`
-	OutputDir         string  `json:"outputDir,omitempty"`
-	GifFps            int     `json:"gifFps,omitempty"`
-	GifScale          int     `json:"gifScale,omitempty"`
-	Theme             string  `json:"theme,omitempty"`
+	LastStartedDevice *Device                   `json:"lastStartedDevice,omitempty"`
+	DefaultDevice     string                    `json:"defaultDevice,omitempty"`
+	DefaultApp        string                    `json:"defaultApp,omitempty"`
+	OutputDir         string                    `json:"outputDir,omitempty"`
+	GifFps            int                       `json:"gifFps,omitempty"`
+	GifScale          int                       `json:"gifScale,omitempty"`
+	Theme             string                    `json:"theme,omitempty"`
+	NetworkProfiles   map[string]NetworkProfile `json:"networkProfiles,omitempty"`
+	Deeplinks         map[string]string         `json:"deeplinks,omitempty"`
+	Frames            map[string]string         `json:"frames,omitempty"`
+	FrameBackground   string                    `json:"frameBackground,omitempty"`
+	FramePadding      int                       `json:"framePadding,omitempty"`
 }
 
 // GetConfigDir returns the path to the sim-cli configuration directory.
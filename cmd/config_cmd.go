@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -40,9 +41,13 @@ var configSetCmd = &cobra.Command{
 	Short: "Set a configuration value",
 	Long: `Set a configuration value. Supported keys:
 - defaultDevice (string)
+- defaultApp (string, bundle ID or package name)
 - outputDir (string)
 - gifFps (int)
-- gifScale (int)`,
+- gifScale (int)
+- frameBackground (transparent or #rrggbb, for screenshot --frame)
+- framePadding (int, pixels around the frame)
+- frame.<device-type-or-skin> (path to a PNG frame with a transparent screen)`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
@@ -53,9 +58,15 @@ var configSetCmd = &cobra.Command{
 			config = &Config{}
 		}
 
+		if frameKey, ok := strings.CutPrefix(key, "frame."); ok {
+			return setFrameAsset(config, frameKey, value)
+		}
+
 		switch strings.ToLower(key) {
 		case "defaultdevice":
 			config.DefaultDevice = value
+		case "defaultapp":
+			config.DefaultApp = value
 		case "outputdir":
 			config.OutputDir = value
 		case "giffps":
@@ -64,6 +75,17 @@ var configSetCmd = &cobra.Command{
 				return fmt.Errorf("invalid integer value for gifFps: %s", value) //nolint:err113
 			}
 			config.GifFps = fps
+		case "framebackground":
+			if _, err := ParseFrameBackground(value); err != nil {
+				return err
+			}
+			config.FrameBackground = value
+		case "framepadding":
+			padding, err := strconv.Atoi(value)
+			if err != nil || padding < 0 {
+				return fmt.Errorf("invalid value for framePadding: %s", value) //nolint:err113
+			}
+			config.FramePadding = padding
 		case "gifscale":
 			scale, err := strconv.Atoi(value)
 			if err != nil {
@@ -84,6 +106,39 @@ var configSetCmd = &cobra.Command{
 	},
 }
 
+// setFrameAsset checks that the PNG at value has a transparent screen area and saves it
+// as the frame for key.
+func setFrameAsset(config *Config, key, value string) error {
+	if key == "" {
+		return fmt.Errorf("frame key missing: use frame.<device-type-or-skin>, e.g. frame.iPhone-15-Pro") //nolint:err113
+	}
+
+	asset, err := filepath.Abs(value)
+	if err != nil {
+		return err
+	}
+	frame, err := readPNG(asset)
+	if err != nil {
+		return err
+	}
+	if _, err := FindFrameScreen(frame); err != nil {
+		return fmt.Errorf("frame %s: %w", value, err)
+	}
+
+	if config.Frames == nil {
+		config.Frames = map[string]string{}
+	}
+	config.Frames[key] = asset
+
+	if err := SaveConfig(config); err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Set frame for %s = %s", key, asset))
+
+	return nil
+}
+
 var configResetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset configuration to defaults",
@@ -25,6 +25,10 @@ const (
 	CmdFFmpeg           = "ffmpeg"
 	CmdOsaScript        = "osascript"
 	CmdXclip            = "xclip"
+	CmdPlutil           = "plutil"
+	CmdNetworkSetup     = "networksetup"
+	CmdScutil           = "scutil"
+	CmdCodesign         = "codesign"
 	PrefixScreenshot    = "screenshot"
 	PrefixRecording     = "recording"
 
@@ -3,140 +3,458 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+// CopyOptions controls a copy between the host and a device.
+type CopyOptions struct {
+	Verify bool // Compare sha256 sums on both sides after copying.
+}
+
 var copyCmd = &cobra.Command{
 	Use:   "copy",
 	Short: "Copy files to or from a device",
-	Long: `Copy files to or from a device.
-For iOS, 'copy to' adds media to the Photos app. 'copy from' is currently not supported for iOS.
-For Android, 'copy to' pushes to /sdcard/Download/ and 'copy from' pulls from the specified path.`,
+	Long: `Copy files and directories to or from a device.
+
+Sources may be directories (copied recursively) or glob patterns. A destination
+ending in '/' receives the sources by name; so does any destination when there
+are several sources. Large files show a progress bar, and --verify compares
+sha256 sums on both sides once the copy finishes.
+
+For iOS, 'copy to' adds media to the Photos app, and both directions can address files
+inside an installed app with app:<bundle-id>[:<container>]/<path>. The container is
+data (default), app or an app group identifier (group.*).
+For Android, 'copy to' pushes to /sdcard/Download/ unless a device path is given,
+and 'copy from' pulls from the specified path.`,
 }
 
 var copyToCmd = &cobra.Command{
-	Use:   "to [device-name-or-udid] <local-path>",
-	Short: "Copy a file to a device",
-	Args:  cobra.RangeArgs(1, 2),
+	Use:   "to [device-name-or-udid] <local-path>... [remote-path]",
+	Short: "Copy files to a device",
+	Long: `Copy files to a device.
+
+With two or more paths the last one is the destination when it is an app: path (iOS)
+or an absolute path that is not a file on the host (Android); otherwise every path is
+a source, so several photos can be added to iOS Photos at once. Use --dest to give the destination
+explicitly, in which case every path is a source.
+
+Examples:
+  sim copy to photo.jpg
+  sim copy to "iPhone 15" a.jpg b.jpg clip.mov
+  sim copy to ./assets /sdcard/Android/data/com.example.app/files/assets/
+  sim copy to "Pixel_7" "*.json" /sdcard/fixtures/ --verify
+  sim copy to "iPhone 15" fixture.json app:com.example.app/Documents/
+  sim copy to config.plist app:com.example.app:group.com.example.shared/Library/Preferences/
+  sim copy to a.json b.json --dest /sdcard/fixtures/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dest, _ := cmd.Flags().GetString("dest")
+		deviceID, sources, dest := splitCopyToArgs(args, dest)
+		verify, _ := cmd.Flags().GetBool("verify")
+
+		return CopyTo(deviceID, sources, dest, CopyOptions{Verify: verify})
+	},
+}
+
+var copyFromCmd = &cobra.Command{
+	Use:   "from [device-name-or-udid] <remote-path>... [local-path]",
+	Short: "Copy files from a device",
+	Long: `Copy files from a device.
+
+On Android the remote path is a device path, directory or glob. On iOS it addresses
+files inside an installed app with app:<bundle-id>[:<container>]/<path>.
+
+Examples:
+  sim copy from /sdcard/Download/log.txt
+  sim copy from "/sdcard/DCIM/Camera/*.jpg" ./photos/
+  sim copy from app:com.example.app/Documents/db.sqlite ./db.sqlite --verify
+  sim copy from app:com.example.app:app/Info.plist`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var deviceID, localPath string
-		if len(args) == 1 {
-			localPath = args[0]
-		} else {
+		var deviceID string
+
+		switch {
+		case len(args) >= 3 && !looksLikeCopyPath(args[0]):
 			deviceID = args[0]
-			localPath = args[1]
+			args = args[1:]
+		case len(args) == 2 && !looksLikeCopyPath(args[0]):
+			// arg0 might be a device
+			_, _, _, err := FindRunningDevice(args[0])
+			if err == nil {
+				deviceID = args[0]
+				args = args[1:]
+			} else if !errors.Is(err, ErrDeviceNotRunning) && !errors.Is(err, ErrDeviceNotFound) && !errors.Is(err, ErrNoActiveDevice) {
+				// Transient error during device lookup
+				return err
+			}
 		}
 
-		udid, name, isAndroid, err := FindRunningDevice(deviceID)
-		if err != nil {
-			return err
+		remotes, localPath := args, "."
+		if len(args) > 1 {
+			remotes, localPath = args[:len(args)-1], args[len(args)-1]
 		}
 
-		absPath, err := filepath.Abs(localPath)
-		if err != nil {
-			return fmt.Errorf("invalid local path: %w", err)
+		verify, _ := cmd.Flags().GetBool("verify")
+
+		return CopyFrom(deviceID, remotes, localPath, CopyOptions{Verify: verify})
+	},
+}
+
+func init() {
+	copyToCmd.Flags().Bool("verify", false, "Compare sha256 sums on both sides after copying")
+	copyToCmd.Flags().String("dest", "", "Destination on the device; every positional path is then a source")
+	copyFromCmd.Flags().Bool("verify", false, "Compare sha256 sums on both sides after copying")
+	copyCmd.AddCommand(copyToCmd)
+	copyCmd.AddCommand(copyFromCmd)
+}
+
+// looksLikeCopyPath reports whether a copy argument is a path rather than a device name.
+func looksLikeCopyPath(arg string) bool {
+	return strings.Contains(arg, "/") || strings.Contains(arg, "\\") || IsAppPath(arg)
+}
+
+// splitCopyToArgs splits 'copy to' arguments into device, local sources and destination.
+// The first argument is the device when it is not a local path. Unless dest was given with
+// --dest, the last remaining argument is the destination when at least two remain and it is
+// a destination (see isCopyDestArg); otherwise every argument is a source.
+func splitCopyToArgs(args []string, destFlag string) (deviceID string, sources []string, dest string) {
+	if len(args) >= 2 && !hasGlobMeta(args[0]) {
+		if _, err := os.Stat(args[0]); err != nil {
+			deviceID = args[0]
+			args = args[1:]
 		}
+	}
 
+	dest = destFlag
+	if dest == "" && len(args) >= 2 && isCopyDestArg(args[len(args)-1]) {
+		dest = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	return deviceID, args, dest
+}
+
+// isCopyDestArg reports whether the last 'copy to' argument names a destination: an app:
+// path, or an absolute path that is not a file on the host.
+func isCopyDestArg(arg string) bool {
+	if IsAppPath(arg) {
+		return true
+	}
+	if !path.IsAbs(arg) {
+		return false
+	}
+	info, err := os.Stat(arg)
+
+	return err != nil || info.IsDir()
+}
+
+// CopyTo copies host files, directories or glob matches to a device. dest is an absolute
+// device path on Android (default /sdcard/Download/) or an app: path on iOS; without one,
+// iOS adds the files to Photos. Pass an empty deviceID to use the first running device.
+func CopyTo(deviceID string, sources []string, dest string, opts CopyOptions) error {
+	sources, err := expandLocalPaths(sources)
+	if err != nil {
+		return err
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case IsAppPath(dest):
 		if isAndroid {
-			err = RunSpinner(fmt.Sprintf("Copying %s to '%s'...", filepath.Base(absPath), name), func() error {
-				if pushErr := packageExecutor.Run(CmdAdb, "-s", udid, "push", absPath, "/sdcard/Download/"); pushErr != nil {
-					return fmt.Errorf("failed to copy to Android: %w", pushErr)
-				}
+			return fmt.Errorf("%w: app: paths address iOS app containers", ErrUnsupportedPlatform)
+		}
 
-				return nil
-			})
-			if err == nil {
-				PrintSuccess("File copied successfully to /sdcard/Download/")
-			}
-		} else {
-			if runtime.GOOS != DarwinOS {
-				return ErrIOSMacOnly
-			}
-			err = RunSpinner(fmt.Sprintf("Copying %s to '%s'...", filepath.Base(absPath), name), func() error {
-				if addErr := packageExecutor.Run(CmdXCrun, CmdSimctl, "addmedia", udid, absPath); addErr != nil {
-					return fmt.Errorf("failed to add media to iOS simulator: %w", addErr)
-				}
+		appPath, err := ParseAppPath(dest)
+		if err != nil {
+			return err
+		}
+		target, err := ResolveAppPath(udid, appPath)
+		if err != nil {
+			return err
+		}
 
-				return nil
-			})
-			if err == nil {
-				PrintSuccess("Media added successfully to Photos.")
-			}
+		PrintInfo(fmt.Sprintf("Copying to %s on '%s'...", appPath, name))
+
+		return copyHostFiles(sources, target, false, opts)
+	case isAndroid:
+		if dest == "" {
+			dest = defaultAndroidCopyDir
+		}
+		if !path.IsAbs(dest) {
+			return fmt.Errorf("remote path %q must be absolute, e.g. /sdcard/Download/", dest) //nolint:err113
 		}
 
+		return pushAndroidFiles(udid, name, sources, dest, opts)
+	case dest != "":
+		return fmt.Errorf("%w: iOS destinations are app:<bundle-id>/<path>", ErrInvalidAppPath)
+	}
+
+	if runtime.GOOS != DarwinOS {
+		return ErrIOSMacOnly
+	}
+
+	files, err := planCopy(sources, "", true, filepath.Join)
+	if err != nil {
 		return err
-	},
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.src
+	}
+
+	err = RunSpinner(fmt.Sprintf("Adding %d file(s) to Photos on '%s'...", len(paths), name), func() error {
+		if addErr := packageExecutor.Run(CmdXCrun, append([]string{CmdSimctl, "addmedia", udid}, paths...)...); addErr != nil {
+			return fmt.Errorf("failed to add media to iOS simulator: %w", addErr)
+		}
+
+		return nil
+	})
+	if err == nil {
+		PrintSuccess("Media added successfully to Photos.")
+		if opts.Verify {
+			PrintInfo("--verify does not apply to Photos imports.")
+		}
+	}
+
+	return err
 }
 
-var copyFromCmd = &cobra.Command{
-	Use:   "from [device-name-or-udid] <remote-path> [local-path]",
-	Short: "Copy a file from a device (Android only)",
-	Args:  cobra.RangeArgs(1, 3),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		var deviceID, remotePath, localPath string
-
-		// Parse args depending on length
-		switch len(args) {
-		case 1:
-			remotePath = args[0]
-			localPath = "."
-		case 2:
-			if strings.Contains(args[0], "/") || strings.Contains(args[0], "\\") {
-				// arg0 looks like a path (remote or local), so arg0=remote, arg1=local
-				remotePath = args[0]
-				localPath = args[1]
-			} else {
-				// arg0 might be a device
-				_, _, _, err := FindRunningDevice(args[0])
-				if err == nil {
-					deviceID = args[0]
-					remotePath = args[1]
-					localPath = "."
-				} else if !errors.Is(err, ErrDeviceNotRunning) && !errors.Is(err, ErrDeviceNotFound) && !errors.Is(err, ErrNoActiveDevice) {
-					// Transient error during device lookup
-					return err
-				} else {
-					// Fallback to path
-					remotePath = args[0]
-					localPath = args[1]
-				}
+// CopyFrom copies device files, directories or glob matches to localPath. remotes are
+// device paths on Android and app: paths on iOS. Pass an empty deviceID to use the first
+// running device.
+func CopyFrom(deviceID string, remotes []string, localPath string, opts CopyOptions) error {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	intoDir := len(remotes) > 1 || strings.HasSuffix(localPath, "/") || strings.HasSuffix(localPath, string(filepath.Separator))
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		intoDir = true
+	}
+
+	if isAndroid {
+		for _, remote := range remotes {
+			if IsAppPath(remote) {
+				return fmt.Errorf("%w: app: paths address iOS app containers", ErrUnsupportedPlatform)
 			}
-		case 3:
-			deviceID = args[0]
-			remotePath = args[1]
-			localPath = args[2]
 		}
 
-		udid, name, isAndroid, err := FindRunningDevice(deviceID)
+		return pullAndroidFiles(udid, name, remotes, localPath, intoDir, opts)
+	}
+
+	var sources []string
+	for _, remote := range remotes {
+		if !IsAppPath(remote) {
+			return fmt.Errorf("%w: iOS simulators copy from app containers, e.g. app:<bundle-id>/Documents/<file>", ErrInvalidAppPath)
+		}
+
+		appPath, err := ParseAppPath(remote)
+		if err != nil {
+			return err
+		}
+		source, err := ResolveAppPath(udid, appPath)
 		if err != nil {
 			return err
 		}
+		sources = append(sources, source)
+	}
+
+	sources, err = expandLocalPaths(sources)
+	if err != nil {
+		return err
+	}
 
-		if !isAndroid {
-			return fmt.Errorf("copy from is not supported for iOS simulators") //nolint:err113
+	return copyHostFiles(sources, localPath, intoDir, opts)
+}
+
+// pushAndroidFiles pushes host sources to dest on an Android device.
+func pushAndroidFiles(udid, name string, sources []string, dest string, opts CopyOptions) error {
+	intoDir := strings.HasSuffix(dest, "/") || len(sources) > 1
+	if !intoDir {
+		out, _ := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "[ -d "+shellQuote(dest)+" ] && echo dir")
+		intoDir = strings.TrimSpace(string(out)) == "dir"
+	}
+
+	files, err := planCopy(sources, dest, intoDir, path.Join)
+	if err != nil {
+		return err
+	}
+
+	total, err := pushAndroidTransfer(udid, files)
+	if err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Copied %d file(s) (%s) to %s on '%s'.", len(files), formatBytes(total), dest, name))
+
+	if !opts.Verify {
+		return nil
+	}
+
+	return verifyTransfer(files, true, func(paths []string) (map[string]string, error) {
+		return androidSHA256(udid, paths)
+	})
+}
+
+// pullAndroidFiles pulls device paths, directories or globs to localPath.
+func pullAndroidFiles(udid, name string, remotes []string, localPath string, intoDir bool, opts CopyOptions) error {
+	var files, unlisted []transferFile
+	for _, remote := range remotes {
+		planned := planAndroidPull(udid, remote, localPath, intoDir)
+		if planned == nil {
+			// The device could not list the path; let adb pull resolve it.
+			unlisted = append(unlisted, transferFile{src: remote, dst: localPath, size: -1})
+			continue
 		}
+		files = append(files, planned...)
+	}
 
-		err = RunSpinner(fmt.Sprintf("Copying %s from '%s'...", remotePath, name), func() error {
-			if pullErr := packageExecutor.Run(CmdAdb, "-s", udid, "pull", remotePath, localPath); pullErr != nil {
-				return fmt.Errorf("failed to pull from Android: %w", pullErr)
+	all := slices.Concat(files, unlisted)
+	bar := newTransferProgress(all)
+	for i, f := range all {
+		bar.start(i, len(all), path.Base(f.src))
+
+		if f.size >= 0 {
+			if err := os.MkdirAll(filepath.Dir(f.dst), 0o755); err != nil {
+				bar.close()
+				return err
 			}
+		}
 
-			return nil
-		})
+		stop := bar.watch(f, func() int64 {
+			info, err := os.Stat(f.dst)
+			if err != nil {
+				return 0
+			}
 
-		if err == nil {
-			PrintSuccess("File copied successfully.")
+			return info.Size()
+		})
+		err := packageExecutor.Run(CmdAdb, "-s", udid, "pull", f.src, f.dst)
+		stop()
+		if err != nil {
+			bar.close()
+			return fmt.Errorf("failed to pull from Android: %w", err)
 		}
+		bar.finish(f.size)
+	}
+	bar.close()
+
+	if len(files) > 0 {
+		PrintSuccess(fmt.Sprintf("Copied %d file(s) (%s) from '%s' to %s.", len(all), formatBytes(bar.total), name, localPath))
+	} else {
+		PrintSuccess("File copied successfully.")
+	}
+
+	if !opts.Verify {
+		return nil
+	}
+	if len(unlisted) > 0 {
+		return fmt.Errorf("%w: the device could not list %s", ErrVerifyFailed, unlisted[0].src)
+	}
+
+	return verifyTransfer(files, false, func(paths []string) (map[string]string, error) {
+		return androidSHA256(udid, paths)
+	})
+}
+
+// copyHostFiles copies host sources to dest for iOS app containers, which live on the host.
+func copyHostFiles(sources []string, dest string, intoDir bool, opts CopyOptions) error {
+	if len(sources) > 1 || strings.HasSuffix(dest, string(filepath.Separator)) {
+		intoDir = true
+	}
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		intoDir = true
+	}
 
+	files, err := planCopy(sources, dest, intoDir, filepath.Join)
+	if err != nil {
 		return err
-	},
+	}
+
+	total, err := copyHostTransfer(files)
+	if err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Copied %d file(s) (%s) to %s.", len(files), formatBytes(total), dest))
+
+	if !opts.Verify {
+		return nil
+	}
+
+	// Both sides are host files; hash the copies as the device side.
+	return verifyTransfer(files, true, hostSHA256Map)
 }
 
-func init() {
-	copyCmd.AddCommand(copyToCmd)
-	copyCmd.AddCommand(copyFromCmd)
+// pushAndroidTransfer creates the remote directories of files and pushes each one,
+// returning the bytes copied.
+func pushAndroidTransfer(udid string, files []transferFile) (int64, error) {
+	mkdir := []string{"-s", udid, "shell", "mkdir", "-p"}
+	seen := map[string]bool{}
+	for _, f := range files {
+		if dir := path.Dir(f.dst); !seen[dir] {
+			seen[dir] = true
+			mkdir = append(mkdir, shellQuote(dir))
+		}
+	}
+	if out, err := packageExecutor.Output(CmdAdb, mkdir...); err != nil {
+		return 0, fmt.Errorf("failed to create remote directories: %w\nOutput: %s", err, string(out))
+	}
+
+	bar := newTransferProgress(files)
+	defer bar.close()
+
+	for i, f := range files {
+		bar.start(i, len(files), filepath.Base(f.src))
+
+		// adb push keeps the file name when given the parent directory.
+		target := f.dst
+		if path.Base(f.dst) == filepath.Base(f.src) {
+			target = strings.TrimSuffix(path.Dir(f.dst), "/") + "/"
+		}
+
+		stop := bar.watch(f, func() int64 {
+			out, _ := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "stat", "-c", "%s", shellQuote(f.dst))
+			n, _ := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+
+			return n
+		})
+		err := packageExecutor.Run(CmdAdb, "-s", udid, "push", f.src, target)
+		stop()
+		if err != nil {
+			return 0, fmt.Errorf("failed to copy to Android: %w", err)
+		}
+		bar.finish(f.size)
+	}
+
+	return bar.total, nil
+}
+
+// copyHostTransfer copies files between host paths, returning the bytes copied.
+func copyHostTransfer(files []transferFile) (int64, error) {
+	bar := newTransferProgress(files)
+	defer bar.close()
+
+	for i, f := range files {
+		bar.start(i, len(files), filepath.Base(f.src))
+		if err := writeHostFile(f.src, f.dst, bar.set); err != nil {
+			return 0, fmt.Errorf("failed to copy %s: %w", f.src, err)
+		}
+		bar.finish(f.size)
+	}
+
+	return bar.total, nil
 }
@@ -227,18 +227,10 @@ func devicesToRows(devices []Device) []table.Row {
 	return rows
 }
 
-func runDashboard(initialDevices []Device) error {
-	columns := []table.Column{
-		{Title: "Type", Width: 20},
-		{Title: "Name", Width: 35},
-		{Title: "State", Width: 15},
-		{Title: "UDID", Width: 40},
-		{Title: "Runtime", Width: 20},
-	}
-
+// newStyledTable returns a focused table with the dashboard's header and selection styles.
+func newStyledTable(columns []table.Column) table.Model {
 	t := table.New(
 		table.WithColumns(columns),
-		table.WithRows(devicesToRows(initialDevices)),
 		table.WithFocused(true),
 		table.WithHeight(15),
 	)
@@ -255,6 +247,21 @@ func runDashboard(initialDevices []Device) error {
 		Bold(false)
 	t.SetStyles(s)
 
+	return t
+}
+
+func runDashboard(initialDevices []Device) error {
+	columns := []table.Column{
+		{Title: "Type", Width: 20},
+		{Title: "Name", Width: 35},
+		{Title: "State", Width: 15},
+		{Title: "UDID", Width: 40},
+		{Title: "Runtime", Width: 20},
+	}
+
+	t := newStyledTable(columns)
+	t.SetRows(devicesToRows(initialDevices))
+
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 	sp.Style = lipgloss.NewStyle().Foreground(ColorHeader)
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// deeplinkPrefix marks an open target as a saved deeplink name.
+const deeplinkPrefix = "@"
+
+var (
+	// deeplinkNamePattern restricts saved names to characters that need no shell quoting.
+	deeplinkNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	// deeplinkParamPattern matches {name} parameters in a saved deeplink.
+	deeplinkParamPattern = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+)
+
+// Deeplinks returns the saved deeplinks from the config file, keyed by name.
+func Deeplinks() map[string]string {
+	config, err := LoadConfig()
+	if err != nil || config.Deeplinks == nil {
+		return map[string]string{}
+	}
+
+	return config.Deeplinks
+}
+
+// DeeplinkNames returns the saved deeplink names, sorted.
+func DeeplinkNames() []string {
+	names := make([]string, 0, len(Deeplinks()))
+	for name := range Deeplinks() {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// DeeplinkParams returns the {name} parameters of a deeplink in order of appearance.
+func DeeplinkParams(link string) []string {
+	var params []string
+	for _, m := range deeplinkParamPattern.FindAllStringSubmatch(link, -1) {
+		if !slices.Contains(params, m[1]) {
+			params = append(params, m[1])
+		}
+	}
+
+	return params
+}
+
+// SaveDeeplink stores link under name in the config file, replacing any existing entry.
+func SaveDeeplink(name, link string) error {
+	name = strings.TrimPrefix(name, deeplinkPrefix)
+	if !deeplinkNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: invalid name %q (use letters, digits, '.', '_' or '-')", ErrInvalidDeeplink, name)
+	}
+	if !strings.Contains(link, ":") {
+		return fmt.Errorf("%w: %q has no scheme", ErrInvalidDeeplink, link)
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		config = &Config{}
+	}
+	if config.Deeplinks == nil {
+		config.Deeplinks = map[string]string{}
+	}
+	config.Deeplinks[name] = link
+
+	if err := SaveConfig(config); err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Deeplink '%s' saved.", name))
+
+	return nil
+}
+
+// DeleteDeeplink removes a saved deeplink from the config file.
+func DeleteDeeplink(name string) error {
+	name = strings.TrimPrefix(name, deeplinkPrefix)
+
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := config.Deeplinks[name]; !ok {
+		return fmt.Errorf("%w: no saved deeplink named %q", ErrInvalidDeeplink, name)
+	}
+	delete(config.Deeplinks, name)
+
+	if err := SaveConfig(config); err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Deeplink '%s' deleted.", name))
+
+	return nil
+}
+
+// ExpandDeeplink resolves an open target to a URL. A target starting with '@' is looked
+// up in the saved deeplinks. Each key=value param replaces {key} in the URL; values are
+// percent-encoded so they are safe in both paths and query strings.
+func ExpandDeeplink(target string, params []string) (string, error) {
+	link := target
+	if name, ok := strings.CutPrefix(target, deeplinkPrefix); ok {
+		saved, found := Deeplinks()[name]
+		if !found {
+			return "", fmt.Errorf("%w: no saved deeplink named %q (see 'sim open --list')", ErrInvalidDeeplink, name)
+		}
+		link = saved
+	}
+
+	values := make(map[string]string, len(params))
+	for _, param := range params {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok || key == "" {
+			return "", fmt.Errorf("%w: parameter %q must be key=value", ErrInvalidDeeplink, param)
+		}
+		if !slices.Contains(DeeplinkParams(link), key) {
+			return "", fmt.Errorf("%w: %s has no {%s} parameter", ErrInvalidDeeplink, target, key)
+		}
+		values[key] = value
+	}
+
+	var missing []string
+	expanded := deeplinkParamPattern.ReplaceAllStringFunc(link, func(m string) string {
+		key := m[1 : len(m)-1]
+		value, ok := values[key]
+		if !ok {
+			if !slices.Contains(missing, key) {
+				missing = append(missing, key)
+			}
+
+			return m
+		}
+
+		return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s needs %s", ErrInvalidDeeplink, target,
+			strings.Join(missing, "=..., ")+"=...")
+	}
+
+	return expanded, nil
+}
@@ -33,4 +33,64 @@ var (
 	ErrIOSMacOnly = errors.New("iOS operations are only supported on macOS")
 	// ErrAndroidCloneNotSupported is returned when attempting to clone an Android emulator.
 	ErrAndroidCloneNotSupported = errors.New("cloning not supported for Android emulators")
+	// ErrInvalidAppID is returned when a bundle ID or package name contains unexpected characters.
+	ErrInvalidAppID = errors.New("invalid bundle ID or package name")
+	// ErrAppNotInstalled is returned when an app cannot be found on the target device.
+	ErrAppNotInstalled = errors.New("app is not installed on the device")
+	// ErrAppNotDebuggable is returned when run-as cannot access an Android app's data.
+	ErrAppNotDebuggable = errors.New("app data is not accessible; the Android app must be debuggable")
+	// ErrInvalidBackup is returned when an app backup archive cannot be read.
+	ErrInvalidBackup = errors.New("invalid app backup archive")
+	// ErrBackupMismatch is returned when a backup does not match the target app or platform.
+	ErrBackupMismatch = errors.New("backup does not match target")
+	// ErrUnknownPrivacyService is returned when a privacy service name is not recognized.
+	ErrUnknownPrivacyService = errors.New("unknown privacy service")
+	// ErrInvalidPrivacyAction is returned when a privacy action is not grant, revoke or reset.
+	ErrInvalidPrivacyAction = errors.New("invalid privacy action")
+	// ErrPrivacyUnsupported is returned when a privacy service has no equivalent on the target platform.
+	ErrPrivacyUnsupported = errors.New("privacy service not supported")
+	// ErrInvalidCoordinate is returned when a latitude/longitude pair cannot be parsed or is out of range.
+	ErrInvalidCoordinate = errors.New("invalid coordinate")
+	// ErrInvalidRoute is returned when a GPX or KML route file cannot be parsed.
+	ErrInvalidRoute = errors.New("invalid route file")
+	// ErrInvalidSpeed is returned when a playback speed is not a positive number.
+	ErrInvalidSpeed = errors.New("invalid playback speed")
+	// ErrInvalidStatusBar is returned when a status bar override value is out of range.
+	ErrInvalidStatusBar = errors.New("invalid status bar override")
+	// ErrInvalidSetting is returned when an appearance or display setting value is not recognized.
+	ErrInvalidSetting = errors.New("invalid setting")
+	// ErrUnsupportedPlatform is returned when an operation has no equivalent on the target platform.
+	ErrUnsupportedPlatform = errors.New("not supported on this platform")
+	// ErrInvalidLocale is returned when a locale, language or timezone identifier is not valid.
+	ErrInvalidLocale = errors.New("invalid locale")
+	// ErrLocaleNotApplied is returned when the device does not report the requested locale after a restart.
+	ErrLocaleNotApplied = errors.New("locale change did not take effect")
+	// ErrInvalidCertificate is returned when a file is not a PEM or DER encoded X.509 certificate.
+	ErrInvalidCertificate = errors.New("invalid certificate")
+	// ErrCertStoreUnavailable is returned when the requested Android certificate store cannot be written.
+	ErrCertStoreUnavailable = errors.New("certificate store not writable")
+	// ErrInvalidProxy is returned when a proxy address is not a valid host:port pair.
+	ErrInvalidProxy = errors.New("invalid proxy address")
+	// ErrInvalidNetworkProfile is returned when a network profile is unknown or has invalid values.
+	ErrInvalidNetworkProfile = errors.New("invalid network profile")
+	// ErrInvalidInputEvent is returned when a coordinate or key for an input event cannot be parsed.
+	ErrInvalidInputEvent = errors.New("invalid input event")
+	// ErrInvalidPushPayload is returned when a push payload or template fails APNs validation.
+	ErrInvalidPushPayload = errors.New("invalid push payload")
+	// ErrNoPushTarget is returned when a push has no bundle ID and none can be inferred.
+	ErrNoPushTarget = errors.New("no push target app")
+	// ErrInvalidDeeplink is returned when a saved deeplink or its parameters are invalid.
+	ErrInvalidDeeplink = errors.New("invalid deeplink")
+	// ErrInvalidIntent is returned when Android intent options are invalid or 'am start' rejects the intent.
+	ErrInvalidIntent = errors.New("invalid intent")
+	// ErrLinkNotVerified is returned when links to a domain would not open in the app.
+	ErrLinkNotVerified = errors.New("link not verified")
+	// ErrInvalidAppPath is returned when an app:<bundle-id>/<path> address cannot be parsed.
+	ErrInvalidAppPath = errors.New("invalid app path")
+	// ErrVerifyFailed is returned when a copied file's checksum differs from the original.
+	ErrVerifyFailed = errors.New("verification failed")
+	// ErrInvalidSeedManifest is returned when a seed manifest cannot be parsed or is inconsistent.
+	ErrInvalidSeedManifest = errors.New("invalid seed manifest")
+	// ErrSeedFailed is returned when one or more steps of a seed run fail.
+	ErrSeedFailed = errors.New("seeding failed")
 )
@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fileBrowserMode is what the file browser is currently showing or asking for.
+type fileBrowserMode int
+
+const (
+	browseMode fileBrowserMode = iota
+	previewMode
+	pushMode
+	confirmDeleteMode
+)
+
+type fileBrowserModel struct {
+	source     fileSource
+	deviceName string
+	dest       string // Local directory for pulls.
+	dir        string
+	files      []RemoteFile
+	mode       fileBrowserMode
+
+	table    table.Model
+	viewport viewport.Model
+	input    textinput.Model
+	spinner  spinner.Model
+
+	previewName  string
+	deleteTarget string // Path confirmed for deletion with 'd'.
+	msg          string
+	loading      bool
+	width        int
+	height       int
+}
+
+type (
+	filesListedMsg struct {
+		dir   string
+		files []RemoteFile
+		err   error
+	}
+	filePreviewMsg struct {
+		name    string
+		content []byte
+		err     error
+	}
+)
+
+func listFilesCmd(source fileSource, dir string) tea.Cmd {
+	return func() tea.Msg {
+		files, err := source.list(dir)
+		return filesListedMsg{dir: dir, files: files, err: err}
+	}
+}
+
+func previewFileCmd(source fileSource, p string) tea.Cmd {
+	return func() tea.Msg {
+		content, err := source.preview(p)
+		return filePreviewMsg{name: p, content: content, err: err}
+	}
+}
+
+// openLinkCmd lists a symlink as a directory, or previews it when it points to a file.
+func openLinkCmd(source fileSource, p string) tea.Cmd {
+	return func() tea.Msg {
+		files, err := source.list(p)
+		if err == nil || len(files) > 0 {
+			return filesListedMsg{dir: p, files: files, err: err}
+		}
+
+		content, previewErr := source.preview(p)
+		if previewErr != nil {
+			return filesListedMsg{dir: p, err: err}
+		}
+
+		return filePreviewMsg{name: p, content: content}
+	}
+}
+
+func (m fileBrowserModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, listFilesCmd(m.source, m.dir))
+}
+
+// selected returns the highlighted entry, if any.
+func (m fileBrowserModel) selected() (RemoteFile, bool) {
+	i := m.table.Cursor()
+	if i < 0 || i >= len(m.files) {
+		return RemoteFile{}, false
+	}
+
+	return m.files[i], true
+}
+
+//nolint:gocyclo,cyclop
+func (m fileBrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.mode {
+		case previewMode:
+			switch msg.String() {
+			case "q", "esc", "left", "h", "backspace":
+				m.mode = browseMode
+			default:
+				var cmd tea.Cmd
+				m.viewport, cmd = m.viewport.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+
+			return m, tea.Batch(cmds...)
+		case pushMode:
+			switch msg.String() {
+			case "esc":
+				m.mode = browseMode
+				m.msg = "Push cancelled."
+			case "enter":
+				localPath := strings.TrimSpace(m.input.Value())
+				m.mode = browseMode
+				if localPath == "" {
+					break
+				}
+				source, dir := m.source, m.dir
+				m.loading = true
+				m.msg = "Pushing " + localPath + "..."
+				cmds = append(cmds, doActionCmd(func() error {
+					return source.push(localPath, dir)
+				}, "Pushed "+filepath.Base(localPath)+" to "+source.display(dir)))
+			default:
+				var cmd tea.Cmd
+				m.input, cmd = m.input.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+
+			return m, tea.Batch(cmds...)
+		case confirmDeleteMode:
+			// The rows may be refreshed while the prompt is open, so delete the entry
+			// that was highlighted when 'd' was pressed rather than the current one.
+			m.mode = browseMode
+			target, source := m.deleteTarget, m.source
+			m.deleteTarget = ""
+			if msg.String() != "y" || target == "" {
+				m.msg = "Delete cancelled."
+				return m, nil
+			}
+			name := path.Base(target)
+			m.loading = true
+			m.msg = "Deleting " + name + "..."
+
+			return m, doActionCmd(func() error {
+				return source.remove(target)
+			}, "Deleted "+name)
+		case browseMode:
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "enter", "right", "l":
+			f, ok := m.selected()
+			if !ok {
+				break
+			}
+			target := path.Join(m.dir, f.Name)
+			m.loading = true
+			switch {
+			case f.IsDir:
+				m.msg = "Opening " + target + "..."
+				cmds = append(cmds, listFilesCmd(m.source, target))
+			case f.Link != "":
+				m.msg = "Opening " + target + "..."
+				cmds = append(cmds, openLinkCmd(m.source, target))
+			default:
+				m.msg = "Loading " + f.Name + "..."
+				cmds = append(cmds, previewFileCmd(m.source, target))
+			}
+		case "backspace", "left", "h":
+			if m.dir != "/" {
+				m.loading = true
+				cmds = append(cmds, listFilesCmd(m.source, path.Dir(m.dir)))
+			}
+		case "p":
+			if f, ok := m.selected(); ok {
+				target, source, dest := path.Join(m.dir, f.Name), m.source, m.dest
+				m.loading = true
+				m.msg = "Pulling " + f.Name + "..."
+				cmds = append(cmds, doActionCmd(func() error {
+					return source.pull(target, dest)
+				}, "Pulled "+f.Name+" to "+filepath.Join(dest, f.Name)))
+			}
+		case "u":
+			m.mode = pushMode
+			m.input.SetValue("")
+			m.input.Focus()
+
+			return m, textinput.Blink
+		case "d":
+			if f, ok := m.selected(); ok {
+				m.mode = confirmDeleteMode
+				m.deleteTarget = path.Join(m.dir, f.Name)
+				m.msg = fmt.Sprintf("Delete %s? [y/N]", f.Name)
+			}
+
+			return m, nil
+		case "r":
+			m.loading = true
+			m.msg = "Refreshing..."
+			cmds = append(cmds, listFilesCmd(m.source, m.dir))
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+		// Title, controls, path, borders and footer.
+		m.table.SetHeight(max(m.height-8, 5))
+		m.viewport.Width = m.width
+		m.viewport.Height = max(m.height-4, 5)
+
+		availWidth := max(m.width-10, 60)
+		wSize := 10
+		wModified := 16
+		wMode := 12
+		m.table.SetColumns([]table.Column{
+			{Title: "Name", Width: availWidth - wSize - wModified - wMode},
+			{Title: "Size", Width: wSize},
+			{Title: "Modified", Width: wModified},
+			{Title: "Mode", Width: wMode},
+		})
+
+	case filesListedMsg:
+		m.loading = false
+		if msg.err != nil && len(msg.files) == 0 {
+			m.msg = "Error: " + msg.err.Error()
+			break
+		}
+		if msg.dir != m.dir {
+			m.table.SetCursor(0)
+		}
+		m.dir = msg.dir
+		m.files = sortRemoteFiles(msg.files)
+		m.table.SetRows(remoteFilesToRows(m.files))
+		m.table.SetCursor(min(m.table.Cursor(), max(len(m.files)-1, 0)))
+		switch {
+		case msg.err != nil:
+			m.msg = fmt.Sprintf("%d entries. Error: %v", len(m.files), msg.err)
+		case strings.HasPrefix(m.msg, "Opening") || m.msg == "Refreshing...":
+			m.msg = fmt.Sprintf("%d entries.", len(m.files))
+		}
+
+	case filePreviewMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.msg = "Error: " + msg.err.Error()
+			break
+		}
+		m.msg = ""
+		m.mode = previewMode
+		m.previewName = msg.name
+		m.viewport.SetContent(previewText(msg.content))
+		m.viewport.GotoTop()
+
+	case actionDoneMsg:
+		m.msg = msg.msg
+		m.loading = false
+		cmds = append(cmds, listFilesCmd(m.source, m.dir))
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if m.mode == browseMode {
+		var tableCmd tea.Cmd
+		m.table, tableCmd = m.table.Update(msg)
+		cmds = append(cmds, tableCmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m fileBrowserModel) View() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(ColorHeader).Render("SIM-CLI Files") + " " + m.deviceName
+
+	if m.mode == previewMode {
+		header := title + "  " + lipgloss.NewStyle().Foreground(ColorIOS).Render(m.source.display(m.previewName))
+		header += "\nControls: [up/down] Scroll • [esc/q] Back\n"
+
+		return header + "\n" + m.viewport.View()
+	}
+
+	s := title + "\nControls: [enter] Open • [backspace] Up • [p] Pull • [u] Push • [d] Delete • [r] Refresh • [q] Quit\n"
+	s += lipgloss.NewStyle().Foreground(ColorIOS).Render(m.source.display(m.dir)) + "\n"
+	s += dashboardBaseStyle.Render(m.table.View()) + "\n"
+
+	switch {
+	case m.mode == pushMode:
+		s += "Push local path into " + m.source.display(m.dir) + ": " + m.input.View()
+	case m.loading:
+		s += m.spinner.View() + " " + lipgloss.NewStyle().Foreground(ColorIOS).Render(m.msg)
+	case m.msg != "":
+		s += lipgloss.NewStyle().Foreground(ColorIOS).Render("ℹ " + m.msg)
+	}
+
+	return s + "\n"
+}
+
+// sortRemoteFiles orders directories first, then by name.
+func sortRemoteFiles(files []RemoteFile) []RemoteFile {
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].IsDir != files[j].IsDir {
+			return files[i].IsDir
+		}
+
+		return strings.ToLower(files[i].Name) < strings.ToLower(files[j].Name)
+	})
+
+	return files
+}
+
+func remoteFilesToRows(files []RemoteFile) []table.Row {
+	rows := make([]table.Row, 0, len(files))
+	for _, f := range files {
+		name, size := f.Name, formatBytes(f.Size)
+		switch {
+		case f.IsDir:
+			name, size = name+"/", "-"
+		case f.Link != "":
+			name += " -> " + f.Link
+		}
+		rows = append(rows, table.Row{name, size, f.Modified, f.Mode})
+	}
+
+	return rows
+}
+
+// previewText returns content for display, or a note when it is not text.
+func previewText(content []byte) string {
+	if bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content) {
+		return fmt.Sprintf("Binary file (%s shown of at most %s). Press p to pull it.", formatBytes(int64(len(content))), formatBytes(filesPreviewLimit))
+	}
+	if len(content) == filesPreviewLimit {
+		return string(content) + fmt.Sprintf("\n\n… preview truncated at %s", formatBytes(filesPreviewLimit))
+	}
+
+	return string(content)
+}
+
+// newFileBrowserModel returns a browser for source that starts by listing start.
+func newFileBrowserModel(source fileSource, deviceName, start, dest string) fileBrowserModel {
+	t := newStyledTable([]table.Column{
+		{Title: "Name", Width: 40},
+		{Title: "Size", Width: 10},
+		{Title: "Modified", Width: 16},
+		{Title: "Mode", Width: 12},
+	})
+
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = lipgloss.NewStyle().Foreground(ColorHeader)
+
+	input := textinput.New()
+	input.Placeholder = "./local/file"
+
+	return fileBrowserModel{
+		source:     source,
+		deviceName: deviceName,
+		dest:       dest,
+		dir:        start,
+		table:      t,
+		viewport:   viewport.New(80, 20),
+		input:      input,
+		spinner:    sp,
+		msg:        "Opening " + start + "...",
+		loading:    true,
+	}
+}
+
+func runFileBrowser(source fileSource, deviceName, start, dest string) error {
+	m := newFileBrowserModel(source, deviceName, start, dest)
+	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeFileSource records removals and serves a fixed set of files and previews.
+type fakeFileSource struct {
+	dirs    map[string][]RemoteFile
+	files   map[string]string
+	removed []string
+}
+
+func (s *fakeFileSource) display(dir string) string { return dir }
+
+func (s *fakeFileSource) list(dir string) ([]RemoteFile, error) {
+	files, ok := s.dirs[dir]
+	if !ok {
+		return nil, errors.New("not a directory")
+	}
+
+	return files, nil
+}
+
+func (s *fakeFileSource) preview(p string) ([]byte, error) {
+	content, ok := s.files[p]
+	if !ok {
+		return nil, errors.New("no such file")
+	}
+
+	return []byte(content), nil
+}
+
+func (s *fakeFileSource) pull(string, string) error { return nil }
+func (s *fakeFileSource) push(string, string) error { return nil }
+
+func (s *fakeFileSource) remove(p string) error {
+	s.removed = append(s.removed, p)
+	return nil
+}
+
+func testFileBrowser(source fileSource, files []RemoteFile) fileBrowserModel {
+	m := newFileBrowserModel(source, "Pixel_7", "/sdcard", ".")
+	updated, _ := m.Update(filesListedMsg{dir: "/sdcard", files: files})
+
+	return updated.(fileBrowserModel)
+}
+
+func runeKey(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestFileBrowser_DeleteTargetsConfirmedEntry(t *testing.T) {
+	source := &fakeFileSource{}
+	var m tea.Model = testFileBrowser(source, []RemoteFile{{Name: "b.txt"}, {Name: "c.txt"}})
+
+	m, _ = m.Update(runeKey("d"))
+	if got := m.(fileBrowserModel).mode; got != confirmDeleteMode {
+		t.Fatalf("expected the delete prompt, got mode %d", got)
+	}
+
+	// A refresh lands while the prompt is open and puts a new entry under the cursor.
+	m, _ = m.Update(filesListedMsg{dir: "/sdcard", files: []RemoteFile{{Name: "a.txt"}, {Name: "b.txt"}, {Name: "c.txt"}}})
+	if f, _ := m.(fileBrowserModel).selected(); f.Name != "a.txt" {
+		t.Fatalf("expected the refresh to move a.txt under the cursor, got %q", f.Name)
+	}
+
+	_, cmd := m.Update(runeKey("y"))
+	if cmd == nil {
+		t.Fatal("expected a delete command")
+	}
+	cmd()
+
+	if len(source.removed) != 1 || source.removed[0] != "/sdcard/b.txt" {
+		t.Errorf("expected /sdcard/b.txt to be deleted, got %v", source.removed)
+	}
+}
+
+func TestFileBrowser_OpenFileSymlinkPreviews(t *testing.T) {
+	source := &fakeFileSource{
+		dirs:  map[string][]RemoteFile{"/sdcard": nil, "/sdcard/dir-link": {{Name: "x.txt"}}},
+		files: map[string]string{"/sdcard/file-link": "hello"},
+	}
+	var m tea.Model = testFileBrowser(source, []RemoteFile{
+		{Name: "dir-link", Link: "/data/dir"},
+		{Name: "file-link", Link: "/data/file.txt"},
+	})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(cmd())
+	if got := m.(fileBrowserModel); got.mode != previewMode || got.previewName != "/sdcard/file-link" {
+		t.Errorf("expected a symlink to a file to be previewed, got mode %d (%s)", got.mode, got.msg)
+	}
+
+	m, _ = m.Update(runeKey("q"))
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(cmd())
+	if got := m.(fileBrowserModel); got.mode != browseMode || got.dir != "/sdcard/dir-link" {
+		t.Errorf("expected a symlink to a directory to be opened, got mode %d in %s", got.mode, got.dir)
+	}
+}
+
+// failingLsExecutor answers every command with out and a non-zero exit.
+type failingLsExecutor struct {
+	*OSCommandExecutor
+	out string
+}
+
+func (e failingLsExecutor) Output(string, ...string) ([]byte, error) {
+	return []byte(e.out), errors.New("exit status 1")
+}
+
+func TestFileBrowser_PartialListingShowsEntries(t *testing.T) {
+	SetExecutor(failingLsExecutor{OSCommandExecutor: &OSCommandExecutor{}, out: `total 8
+drwxrwx--x 3 system system 4096 2024-01-01 10:00 .
+ls: /sdcard/secret: Permission denied
+-rw-rw---- 1 u0_a1 u0_a1   12 2024-01-01 10:00 notes.txt
+`})
+	t.Cleanup(func() { SetExecutor(&OSCommandExecutor{}) })
+
+	source := androidFileSource{udid: "emulator-5554"}
+	m := newFileBrowserModel(source, "Pixel_7", "/sdcard", ".")
+	updated, _ := m.Update(listFilesCmd(source, "/sdcard")())
+
+	got := updated.(fileBrowserModel)
+	if len(got.files) != 1 || got.files[0].Name != "notes.txt" {
+		t.Errorf("expected the readable entry to be listed, got %v", got.files)
+	}
+	if !strings.Contains(got.msg, "Error") {
+		t.Errorf("expected the ls failure in the status line, got %q", got.msg)
+	}
+
+	SetExecutor(failingLsExecutor{OSCommandExecutor: &OSCommandExecutor{}, out: "ls: /sdcard/secret: Permission denied\n"})
+	updated, _ = got.Update(listFilesCmd(source, "/sdcard/secret")())
+	if got := updated.(fileBrowserModel); got.dir != "/sdcard" || !strings.HasPrefix(got.msg, "Error: ") {
+		t.Errorf("expected an unreadable directory to stay an error, got %s (%q)", got.dir, got.msg)
+	}
+}
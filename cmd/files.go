@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultAndroidBrowseDir is where 'sim files' starts on Android.
+	defaultAndroidBrowseDir = "/sdcard"
+	// filesPreviewLimit bounds how much of a file the browser reads for a preview.
+	filesPreviewLimit = 64 << 10
+)
+
+// lsLinePattern matches an 'ls -la' entry: mode, links, owner, group, size (or the
+// major, minor of a device node), date, time and name.
+var lsLinePattern = regexp.MustCompile(`^([-dlcbps][-rwxsStT]{9}\S*)\s+\d+\s+\S+\s+\S+\s+(\d+|\d+,\s*\d+)\s+(\d{4}-\d{2}-\d{2} \d{2}:\d{2})\s+(.+)$`)
+
+// RemoteFile is an entry listed by the file browser.
+type RemoteFile struct {
+	Name     string
+	Size     int64
+	Mode     string
+	Modified string
+	IsDir    bool
+	Link     string // Symlink target, if any.
+}
+
+var filesCmd = &cobra.Command{
+	Use:   "files [device-name-or-udid] [path]",
+	Short: "Browse device files interactively",
+	Long: `Browse device files in an interactive terminal UI.
+
+On Android the browser starts at /sdcard, or at the given absolute path. On iOS it
+browses an installed app's container, given as app:<bundle-id>[:<container>]/<path>.
+
+Keys:
+  enter/→    open a directory or preview a file
+  backspace/← go to the parent directory
+  p          pull the selected entry to the --dest directory
+  u          push a local file or directory into the current directory
+  d          delete the selected entry (asks for confirmation)
+  r          refresh
+  q          quit
+
+Examples:
+  sim files
+  sim files Pixel_7 /sdcard/Android/data/com.example.app/files
+  sim files "iPhone 15" app:com.example.app/Documents --dest ./pulled`,
+	Args:              cobra.MaximumNArgs(2),
+	ValidArgsFunction: validDeviceArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID, start string
+
+		switch {
+		case len(args) == 2:
+			deviceID, start = args[0], args[1]
+		case len(args) == 1 && (strings.HasPrefix(args[0], "/") || IsAppPath(args[0])):
+			start = args[0]
+		case len(args) == 1:
+			deviceID = args[0]
+		}
+
+		dest, _ := cmd.Flags().GetString("dest")
+
+		return BrowseFiles(deviceID, start, dest)
+	},
+}
+
+func init() {
+	filesCmd.Flags().String("dest", ".", "Local directory that pulled files are saved to")
+}
+
+// BrowseFiles opens the file browser on a device. start is an absolute Android path or
+// an iOS app: path. Pass an empty deviceID to use the first running device.
+func BrowseFiles(deviceID, start, dest string) error {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	var source fileSource
+	switch {
+	case IsAppPath(start):
+		if isAndroid {
+			return fmt.Errorf("%w: app: paths address iOS app containers", ErrUnsupportedPlatform)
+		}
+
+		appPath, err := ParseAppPath(start)
+		if err != nil {
+			return err
+		}
+		root, err := iosAppContainer(udid, appPath.BundleID, appPath.Container)
+		if err != nil {
+			return err
+		}
+		source = hostFileSource{root: root, label: AppPath{BundleID: appPath.BundleID, Container: appPath.Container}.String()}
+		start = "/" + strings.TrimSuffix(appPath.Path, "/")
+	case isAndroid:
+		if start == "" {
+			start = defaultAndroidBrowseDir
+		}
+		if !path.IsAbs(start) {
+			return fmt.Errorf("remote path %q must be absolute", start) //nolint:err113
+		}
+		source = androidFileSource{udid: udid}
+	default:
+		return fmt.Errorf("%w: browse an iOS app container with app:<bundle-id>[/<path>]", ErrInvalidAppPath)
+	}
+
+	return runFileBrowser(source, name, path.Clean(start), dest)
+}
+
+// ParseLsOutput parses 'ls -la' output into entries, skipping "." and "..".
+func ParseLsOutput(output string) []RemoteFile {
+	var files []RemoteFile
+	for line := range strings.SplitSeq(output, "\n") {
+		m := lsLinePattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+
+		f := RemoteFile{Mode: m[1], Modified: m[3], Name: m[4], IsDir: m[1][0] == 'd'}
+		f.Size, _ = strconv.ParseInt(m[2], 10, 64)
+		if m[1][0] == 'l' {
+			f.Name, f.Link, _ = strings.Cut(f.Name, " -> ")
+		}
+		if f.Name == "." || f.Name == ".." {
+			continue
+		}
+		files = append(files, f)
+	}
+
+	return files
+}
+
+// fileSource is the device side of the file browser. Paths are slash-separated and
+// absolute; "/" is the root of what the source can browse.
+type fileSource interface {
+	// display formats dir for the browser header.
+	display(dir string) string
+	// list may return entries together with an error when only part of dir could be read.
+	list(dir string) ([]RemoteFile, error)
+	// preview returns up to filesPreviewLimit bytes of a file.
+	preview(p string) ([]byte, error)
+	pull(p, localDir string) error
+	push(localPath, dir string) error
+	remove(p string) error
+}
+
+// androidFileSource browses an Android device through adb.
+type androidFileSource struct {
+	udid string
+}
+
+func (s androidFileSource) display(dir string) string {
+	return dir
+}
+
+func (s androidFileSource) list(dir string) ([]RemoteFile, error) {
+	// The trailing slash lists the target of a symlinked directory such as /sdcard.
+	out, err := packageExecutor.Output(CmdAdb, "-s", s.udid, "shell", "ls", "-la", shellQuote(strings.TrimSuffix(dir, "/")+"/"))
+	files := ParseLsOutput(string(out))
+	if err != nil {
+		// ls exits non-zero when a single entry cannot be read, so keep whatever it listed.
+		return files, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	return files, nil
+}
+
+func (s androidFileSource) preview(p string) ([]byte, error) {
+	out, err := packageExecutor.Output(CmdAdb, "-s", s.udid, "shell", "head", "-c", strconv.Itoa(filesPreviewLimit), shellQuote(p))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", p, err)
+	}
+
+	return out, nil
+}
+
+func (s androidFileSource) pull(p, localDir string) error {
+	if err := os.MkdirAll(localDir, 0o755); err != nil {
+		return err
+	}
+	if err := packageExecutor.Run(CmdAdb, "-s", s.udid, "pull", p, localDir); err != nil {
+		return fmt.Errorf("failed to pull %s: %w", p, err)
+	}
+
+	return nil
+}
+
+func (s androidFileSource) push(localPath, dir string) error {
+	if err := packageExecutor.Run(CmdAdb, "-s", s.udid, "push", localPath, strings.TrimSuffix(dir, "/")+"/"); err != nil {
+		return fmt.Errorf("failed to push %s: %w", localPath, err)
+	}
+
+	return nil
+}
+
+func (s androidFileSource) remove(p string) error {
+	if out, err := packageExecutor.Output(CmdAdb, "-s", s.udid, "shell", "rm", "-rf", shellQuote(p)); err != nil {
+		return fmt.Errorf("failed to delete %s: %w\nOutput: %s", p, err, string(out))
+	}
+
+	return nil
+}
+
+// hostFileSource browses a host directory, such as an iOS app container.
+type hostFileSource struct {
+	root  string
+	label string // Prefix shown in place of root, e.g. app:com.example.app.
+}
+
+func (s hostFileSource) hostPath(p string) string {
+	return filepath.Join(s.root, filepath.FromSlash(path.Clean("/"+p)))
+}
+
+func (s hostFileSource) display(dir string) string {
+	return s.label + strings.TrimPrefix(dir, "/")
+}
+
+func (s hostFileSource) list(dir string) ([]RemoteFile, error) {
+	entries, err := os.ReadDir(s.hostPath(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]RemoteFile, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, RemoteFile{
+			Name:     e.Name(),
+			Size:     info.Size(),
+			Mode:     info.Mode().String(),
+			Modified: info.ModTime().Format("2006-01-02 15:04"),
+			IsDir:    e.IsDir(),
+		})
+	}
+
+	return files, nil
+}
+
+func (s hostFileSource) preview(p string) ([]byte, error) {
+	f, err := os.Open(s.hostPath(p))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return io.ReadAll(io.LimitReader(f, filesPreviewLimit))
+}
+
+func (s hostFileSource) pull(p, localDir string) error {
+	files, err := planCopy([]string{s.hostPath(p)}, localDir, true, filepath.Join)
+	if err != nil {
+		return err
+	}
+
+	return copyHostBatch(files)
+}
+
+func (s hostFileSource) push(localPath, dir string) error {
+	files, err := planCopy([]string{localPath}, s.hostPath(dir), true, filepath.Join)
+	if err != nil {
+		return err
+	}
+
+	return copyHostBatch(files)
+}
+
+func (s hostFileSource) remove(p string) error {
+	if path.Clean("/"+p) == "/" {
+		return fmt.Errorf("refusing to delete the container root") //nolint:err113
+	}
+
+	return os.RemoveAll(s.hostPath(p))
+}
+
+// copyHostBatch copies files without progress output, which would corrupt the browser.
+func copyHostBatch(files []transferFile) error {
+	for _, f := range files {
+		if err := writeHostFile(f.src, f.dst, func(int64) {}); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", f.src, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	// framesDirName is the directory under the config dir searched for <key>.png frames.
+	framesDirName = "frames"
+	// defaultFrameBackground is used when neither the flag nor the config sets one.
+	defaultFrameBackground = "transparent"
+)
+
+// resolutionSkinPattern matches AVD skin names that are only a resolution.
+var resolutionSkinPattern = regexp.MustCompile(`^\d+x\d+$`)
+
+// bezelColor is the body color of the built-in frames.
+var bezelColor = color.RGBA{R: 0x1c, G: 0x1c, B: 0x1e, A: 0xff}
+
+// FrameOptions controls how a screenshot is framed.
+type FrameOptions struct {
+	Background color.Color
+	Padding    int // Pixels of background around the frame.
+}
+
+// FrameScreenshot replaces the PNG at pngPath with the screenshot inside a device frame.
+// The frame is the asset configured for key (a simulator DeviceType such as iPhone-15-Pro
+// or an AVD skin such as pixel_7), or a built-in frame when there is none.
+func FrameScreenshot(pngPath, key string, opts FrameOptions) (string, error) {
+	shot, err := readPNG(pngPath)
+	if err != nil {
+		return "", err
+	}
+
+	asset := frameAsset(key)
+
+	var framed image.Image
+	if asset != "" {
+		frame, err := readPNG(asset)
+		if err != nil {
+			return "", err
+		}
+		if framed, err = ComposeFrame(shot, frame, opts); err != nil {
+			return "", fmt.Errorf("frame %s: %w", asset, err)
+		}
+	} else {
+		framed = ComposeGenericFrame(shot, isTabletFrameKey(key), opts)
+	}
+
+	if err := writePNG(pngPath, framed); err != nil {
+		return "", err
+	}
+
+	if asset == "" {
+		return "built-in frame", nil
+	}
+
+	return filepath.Base(asset), nil
+}
+
+// ComposeFrame draws shot into the transparent screen area of frame, over the background.
+// The screenshot is scaled to cover the screen area, keeping its aspect ratio.
+func ComposeFrame(shot, frame image.Image, opts FrameOptions) (image.Image, error) {
+	screen, err := FindFrameScreen(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	sb := shot.Bounds()
+	if (sb.Dx() > sb.Dy()) != (screen.Dx() > screen.Dy()) {
+		return nil, fmt.Errorf("the screenshot is %dx%d but the frame's screen is %dx%d; use a frame for this orientation", //nolint:err113
+			sb.Dx(), sb.Dy(), screen.Dx(), screen.Dy())
+	}
+
+	canvas, offset := frameCanvas(frame.Bounds().Size(), opts)
+	screen = screen.Sub(frame.Bounds().Min).Add(offset)
+
+	// Scale to cover the screen area and centre the overflow.
+	scale := math.Max(float64(screen.Dx())/float64(sb.Dx()), float64(screen.Dy())/float64(sb.Dy()))
+	w, h := int(math.Ceil(float64(sb.Dx())*scale)), int(math.Ceil(float64(sb.Dy())*scale))
+	dst := image.Rect(0, 0, w, h).Add(screen.Min).Add(image.Pt((screen.Dx()-w)/2, (screen.Dy()-h)/2))
+
+	scaled := image.NewRGBA(canvas.Bounds())
+	xdraw.CatmullRom.Scale(scaled, dst, shot, sb, xdraw.Src, nil)
+	draw.Draw(canvas, screen, scaled, screen.Min, draw.Over)
+	draw.Draw(canvas, frame.Bounds().Sub(frame.Bounds().Min).Add(offset), frame, frame.Bounds().Min, draw.Over)
+
+	return canvas, nil
+}
+
+// ComposeGenericFrame draws shot inside a built-in rounded bezel, over the background.
+func ComposeGenericFrame(shot image.Image, tablet bool, opts FrameOptions) image.Image {
+	sb := shot.Bounds()
+	short := float64(min(sb.Dx(), sb.Dy()))
+
+	bezelRatio, radiusRatio := 0.045, 0.14
+	if tablet {
+		bezelRatio, radiusRatio = 0.04, 0.05
+	}
+	bezel := int(math.Round(short * bezelRatio))
+	outer := short * radiusRatio
+
+	size := image.Pt(sb.Dx()+2*bezel, sb.Dy()+2*bezel)
+	canvas, offset := frameCanvas(size, opts)
+
+	body := image.Rectangle{Min: offset, Max: offset.Add(size)}
+	draw.DrawMask(canvas, body, image.NewUniform(bezelColor), image.Point{}, roundedRect{body, outer}, body.Min, draw.Over)
+
+	screen := body.Inset(bezel)
+	draw.DrawMask(canvas, screen, shot, sb.Min, roundedRect{screen, math.Max(outer-float64(bezel), 0)}, screen.Min, draw.Over)
+
+	return canvas
+}
+
+// frameCanvas returns a canvas for a frame of the given size with padding and background,
+// and where the frame's top-left corner goes.
+func frameCanvas(size image.Point, opts FrameOptions) (*image.RGBA, image.Point) {
+	padding := max(opts.Padding, 0)
+	canvas := image.NewRGBA(image.Rect(0, 0, size.X+2*padding, size.Y+2*padding))
+	if opts.Background != nil {
+		draw.Draw(canvas, canvas.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+	}
+
+	return canvas, image.Pt(padding, padding)
+}
+
+// FindFrameScreen returns the bounds of the transparent screen area around the centre of
+// a frame image.
+func FindFrameScreen(frame image.Image) (image.Rectangle, error) {
+	b := frame.Bounds()
+	transparent := func(p image.Point) bool {
+		_, _, _, a := frame.At(p.X, p.Y).RGBA()
+		return a < 0x8000
+	}
+
+	start := image.Pt(b.Min.X+b.Dx()/2, b.Min.Y+b.Dy()/2)
+	if !transparent(start) {
+		return image.Rectangle{}, fmt.Errorf("the centre of the frame is not transparent") //nolint:err113
+	}
+
+	// Flood fill the transparent region from the centre.
+	seen := make([]bool, b.Dx()*b.Dy())
+	index := func(p image.Point) int { return (p.Y-b.Min.Y)*b.Dx() + p.X - b.Min.X }
+	screen := image.Rectangle{Min: start, Max: start.Add(image.Pt(1, 1))}
+	queue := []image.Point{start}
+	seen[index(start)] = true
+
+	for len(queue) > 0 {
+		p := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		screen = screen.Union(image.Rectangle{Min: p, Max: p.Add(image.Pt(1, 1))})
+
+		for _, d := range []image.Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			n := p.Add(d)
+			if !n.In(b) {
+				return image.Rectangle{}, fmt.Errorf("the frame's screen area is not enclosed by the bezel") //nolint:err113
+			}
+			if i := index(n); !seen[i] && transparent(n) {
+				seen[i] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	return screen, nil
+}
+
+// ParseFrameBackground parses "transparent", "white", "black" or a #rgb, #rrggbb or
+// #rrggbbaa hex color.
+func ParseFrameBackground(s string) (color.Color, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "transparent", "none":
+		return color.Transparent, nil
+	case "white":
+		return color.White, nil
+	case "black":
+		return color.Black, nil
+	}
+
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 8 || err != nil {
+		return nil, fmt.Errorf("invalid frame background %q (use transparent or a #rrggbb color)", s) //nolint:err113
+	}
+
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// frameKey returns the name frames are looked up by for a capture device: the DeviceType
+// of an iOS simulator (e.g. iPhone-15-Pro) or the skin of an Android AVD (e.g. pixel_7).
+func frameKey(c capturer) string {
+	udid, isAndroid := capturerTarget(c)
+	if isAndroid {
+		if skin := androidAVDSkin(c.GetName()); skin != "" {
+			return skin
+		}
+
+		return c.GetName()
+	}
+
+	if d := FindIOSSimulatorByID(udid); d != nil && d.DeviceType != "" {
+		return d.DeviceType[strings.LastIndex(d.DeviceType, ".")+1:]
+	}
+
+	return c.GetName()
+}
+
+// androidAVDSkin reads skin.name, or hw.device.name, from an AVD's config.ini.
+func androidAVDSkin(avdName string) string {
+	avdHome := os.Getenv("ANDROID_AVD_HOME")
+	if avdHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		avdHome = filepath.Join(home, ".android", "avd")
+	}
+
+	f, err := os.Open(filepath.Join(avdHome, avdName+".avd", "config.ini"))
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	// Skins without artwork are named after their resolution, e.g. 1080x2400.
+	if skin := values["skin.name"]; skin != "" && !resolutionSkinPattern.MatchString(skin) {
+		return skin
+	}
+
+	return values["hw.device.name"]
+}
+
+// frameAsset returns the frame PNG for key: the path configured with
+// 'sim config set frame.<key>', or ~/.sim-cli/frames/<key>.png. Keys match without case.
+func frameAsset(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	if config, err := LoadConfig(); err == nil {
+		for k, p := range config.Frames {
+			if strings.EqualFold(k, key) {
+				return p
+			}
+		}
+	}
+
+	dir, err := GetConfigDir()
+	if err != nil {
+		return ""
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, framesDirName))
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		if strings.EqualFold(e.Name(), key+ExtPNG) {
+			return filepath.Join(dir, framesDirName, e.Name())
+		}
+	}
+
+	return ""
+}
+
+// isTabletFrameKey reports whether key names a tablet, which gets a slimmer built-in frame.
+func isTabletFrameKey(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "ipad") || strings.Contains(key, "tablet")
+}
+
+func readPNG(name string) (image.Image, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+
+	return img, nil
+}
+
+func writePNG(name string, img image.Image) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, img); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	return f.Close()
+}
+
+// roundedRect is an anti-aliased rounded rectangle mask.
+type roundedRect struct {
+	r      image.Rectangle
+	radius float64
+}
+
+func (m roundedRect) ColorModel() color.Model { return color.AlphaModel }
+
+func (m roundedRect) Bounds() image.Rectangle { return m.r }
+
+func (m roundedRect) At(x, y int) color.Color {
+	if !image.Pt(x, y).In(m.r) {
+		return color.Alpha{}
+	}
+	if m.radius < 0.5 {
+		return color.Opaque
+	}
+
+	// Distance from the pixel centre to the centre of the nearest corner arc.
+	px, py := float64(x)+0.5, float64(y)+0.5
+	cx := math.Max(float64(m.r.Min.X)+m.radius, math.Min(px, float64(m.r.Max.X)-m.radius))
+	cy := math.Max(float64(m.r.Min.Y)+m.radius, math.Min(py, float64(m.r.Max.Y)-m.radius))
+	coverage := math.Min(math.Max(m.radius-math.Hypot(px-cx, py-cy)+0.5, 0), 1)
+
+	return color.Alpha{A: uint8(coverage * 0xff)}
+}
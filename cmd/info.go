@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var infoCmd = &cobra.Command{
+	Use:               "info [device-name-or-udid]",
+	Short:             "Show details and current settings of a running device",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		return ShowDeviceInfo(deviceID)
+	},
+}
+
+// ShowDeviceInfo prints the platform details and current display settings of a running device.
+func ShowDeviceInfo(deviceID string) error {
+	udid, name, isAndroid, err := findSettingsDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{{"Name", name}, {"ID", udid}}
+
+	if isAndroid {
+		rows = append(rows, []string{"Platform", FormatPlatform(NameAndroid)})
+		if version := androidProp(udid, "ro.build.version.release"); version != "" {
+			rows = append(rows, []string{"OS Version", "Android " + version})
+		}
+		if model := androidProp(udid, "ro.product.model"); model != "" {
+			rows = append(rows, []string{"Model", model})
+		}
+	} else {
+		rows = append(rows, []string{"Platform", FormatPlatform("iOS")})
+		if d := FindIOSSimulatorByID(udid); d != nil {
+			rows = append(rows, []string{"OS Version", FormatRuntime(d.Runtime)})
+			if d.DeviceType != "" {
+				rows = append(rows, []string{"Device Type", d.DeviceType[strings.LastIndex(d.DeviceType, ".")+1:]})
+			}
+		}
+	}
+
+	s := GetDisplaySettings(udid, isAndroid)
+	for _, r := range []struct{ label, value string }{
+		{"Appearance", s.Appearance},
+		{"Text Size", s.TextSize},
+		{"Display Size", s.Size},
+		{"Density", s.Density},
+	} {
+		if r.value != "" {
+			rows = append(rows, []string{r.label, r.value})
+		}
+	}
+
+	RenderTable([]string{"Property", "Value"}, rows)
+
+	return nil
+}
+
+// androidProp returns a system property of an Android emulator, or "" if it cannot be read.
+func androidProp(udid, key string) string {
+	out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "getprop", key)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
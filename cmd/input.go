@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// androidKeyCodes maps friendly key names to Android key events.
+var androidKeyCodes = map[string]string{
+	"home":        "KEYCODE_HOME",
+	"back":        "KEYCODE_BACK",
+	"enter":       "KEYCODE_ENTER",
+	"tab":         "KEYCODE_TAB",
+	"delete":      "KEYCODE_DEL",
+	"escape":      "KEYCODE_ESCAPE",
+	"menu":        "KEYCODE_MENU",
+	"power":       "KEYCODE_POWER",
+	"volume-up":   "KEYCODE_VOLUME_UP",
+	"volume-down": "KEYCODE_VOLUME_DOWN",
+	"app-switch":  "KEYCODE_APP_SWITCH",
+}
+
+var inputCmd = &cobra.Command{
+	Use:   "input",
+	Short: "Send text, taps, swipes and key events to a device",
+	Long: `Inject input events into a running device for quick manual scripting.
+
+Android emulators use 'adb shell input'. Coordinates are pixels, or percentages of the
+screen size such as 50%.
+
+iOS simulators only support 'text', which is typed through the Simulator app's hardware
+keyboard (macOS may ask to allow accessibility access). Other events return an error.`,
+}
+
+var inputTextCmd = &cobra.Command{
+	Use:               "text [device-name-or-udid] <text>",
+	Short:             "Type text into the focused field",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, text := splitDeviceArg(args)
+
+		return InputText(deviceID, text)
+	},
+}
+
+var inputTapCmd = &cobra.Command{
+	Use:               "tap [device-name-or-udid] <x> <y>",
+	Short:             "Tap a point on the screen",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 3 {
+			deviceID, args = args[0], args[1:]
+		}
+
+		return InputTap(deviceID, args[0], args[1])
+	},
+}
+
+var inputSwipeCmd = &cobra.Command{
+	Use:               "swipe [device-name-or-udid] <x1> <y1> <x2> <y2>",
+	Short:             "Swipe between two points",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(4, 5),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 5 {
+			deviceID, args = args[0], args[1:]
+		}
+		duration, _ := cmd.Flags().GetDuration("duration")
+
+		return InputSwipe(deviceID, [4]string{args[0], args[1], args[2], args[3]}, duration)
+	},
+}
+
+var inputKeyCmd = &cobra.Command{
+	Use:   "key [device-name-or-udid] <key>",
+	Short: "Press a key",
+	Long: `Press a key. Named keys: ` + strings.Join(inputKeyNames(), ", ") + `.
+Android key codes (e.g. KEYCODE_CAMERA or 27) are also accepted.`,
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, key := splitDeviceArg(args)
+
+		return InputKey(deviceID, key)
+	},
+}
+
+func init() {
+	inputSwipeCmd.Flags().Duration("duration", 300*time.Millisecond, "Swipe duration")
+
+	inputCmd.AddCommand(inputTextCmd)
+	inputCmd.AddCommand(inputTapCmd)
+	inputCmd.AddCommand(inputSwipeCmd)
+	inputCmd.AddCommand(inputKeyCmd)
+}
+
+func inputKeyNames() []string {
+	names := make([]string, 0, len(androidKeyCodes))
+	for name := range androidKeyCodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseScreenCoordinate converts a pixel value or a percentage such as "50%" of size into pixels.
+func ParseScreenCoordinate(value string, size int) (int, error) {
+	if pct, ok := strings.CutSuffix(value, "%"); ok {
+		p, err := strconv.ParseFloat(pct, 64)
+		if err != nil || p < 0 || p > 100 {
+			return 0, fmt.Errorf("%w: percentage must be 0-100%%, got %q", ErrInvalidInputEvent, value)
+		}
+
+		return int(math.Round(float64(size) * p / 100)), nil
+	}
+
+	px, err := strconv.Atoi(value)
+	if err != nil || px < 0 {
+		return 0, fmt.Errorf("%w: coordinate must be a pixel value or percentage, got %q", ErrInvalidInputEvent, value)
+	}
+
+	return px, nil
+}
+
+// AndroidKeyCode resolves a friendly key name, KEYCODE_* name or numeric code.
+func AndroidKeyCode(key string) (string, error) {
+	if code, ok := androidKeyCodes[strings.ToLower(key)]; ok {
+		return code, nil
+	}
+	if upper := strings.ToUpper(key); strings.HasPrefix(upper, "KEYCODE_") {
+		return upper, nil
+	}
+	if _, err := strconv.Atoi(key); err == nil {
+		return key, nil
+	}
+
+	return "", fmt.Errorf("%w: unknown key %q (named keys: %s)", ErrInvalidInputEvent, key, strings.Join(inputKeyNames(), ", "))
+}
+
+// findInputDevice resolves a running Android emulator; iOS simulators only accept text input.
+func findInputDevice(deviceID, event string) (udid string, err error) {
+	udid, _, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return "", err
+	}
+	if !isAndroid {
+		return "", fmt.Errorf("%w: %s input on iOS simulators", ErrUnsupportedPlatform, event)
+	}
+
+	return udid, nil
+}
+
+// InputText types text into the focused field. Pass an empty deviceID to use the first running device.
+func InputText(deviceID, text string) error {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+
+		// simctl has no text entry, so type through the Simulator app's hardware keyboard.
+		script := fmt.Sprintf(`tell application "Simulator" to activate
+tell application "System Events" to keystroke %q`, text)
+		if err := packageExecutor.Run(CmdOsaScript, "-e", script); err != nil {
+			return fmt.Errorf("failed to type into Simulator (allow accessibility access for your terminal): %w", err)
+		}
+
+		PrintSuccess(fmt.Sprintf("Typed %d characters into the focused Simulator window.", len([]rune(text))))
+
+		return nil
+	}
+
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "input", "text", shellQuote(androidInputText(text))); err != nil {
+		return fmt.Errorf("failed to type text: %w", err)
+	}
+
+	PrintSuccess(fmt.Sprintf("Typed %d characters on '%s'.", len([]rune(text)), name))
+
+	return nil
+}
+
+// InputTap taps x, y (pixels or percentages). Pass an empty deviceID to use the first running device.
+func InputTap(deviceID, x, y string) error {
+	udid, err := findInputDevice(deviceID, "tap")
+	if err != nil {
+		return err
+	}
+
+	points, err := resolveScreenPoints(udid, x, y)
+	if err != nil {
+		return err
+	}
+
+	if err := packageExecutor.Run(CmdAdb, append([]string{"-s", udid, "shell", "input", "tap"}, points...)...); err != nil {
+		return fmt.Errorf("failed to tap: %w", err)
+	}
+
+	PrintSuccess(fmt.Sprintf("Tapped %s,%s.", points[0], points[1]))
+
+	return nil
+}
+
+// InputSwipe swipes from (x1, y1) to (x2, y2) over duration. Pass an empty deviceID to use the first running device.
+func InputSwipe(deviceID string, coords [4]string, duration time.Duration) error {
+	udid, err := findInputDevice(deviceID, "swipe")
+	if err != nil {
+		return err
+	}
+
+	points, err := resolveScreenPoints(udid, coords[:]...)
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"-s", udid, "shell", "input", "swipe"}, points...)
+	args = append(args, strconv.FormatInt(duration.Milliseconds(), 10))
+	if err := packageExecutor.Run(CmdAdb, args...); err != nil {
+		return fmt.Errorf("failed to swipe: %w", err)
+	}
+
+	PrintSuccess(fmt.Sprintf("Swiped %s,%s → %s,%s.", points[0], points[1], points[2], points[3]))
+
+	return nil
+}
+
+// InputKey presses a key. Pass an empty deviceID to use the first running device.
+func InputKey(deviceID, key string) error {
+	code, err := AndroidKeyCode(key)
+	if err != nil {
+		return err
+	}
+
+	udid, err := findInputDevice(deviceID, "key")
+	if err != nil {
+		return err
+	}
+
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "input", "keyevent", code); err != nil {
+		return fmt.Errorf("failed to press %s: %w", key, err)
+	}
+
+	PrintSuccess(fmt.Sprintf("Pressed %s.", key))
+
+	return nil
+}
+
+// resolveScreenPoints converts alternating x, y values into pixels. The screen size is
+// only queried when a percentage is used.
+func resolveScreenPoints(udid string, values ...string) ([]string, error) {
+	var width, height int
+	for _, v := range values {
+		if strings.HasSuffix(v, "%") {
+			var err error
+			if width, height, err = androidScreenSize(udid); err != nil {
+				return nil, err
+			}
+
+			break
+		}
+	}
+
+	points := make([]string, len(values))
+	for i, v := range values {
+		size := width
+		if i%2 == 1 {
+			size = height
+		}
+
+		px, err := ParseScreenCoordinate(v, size)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = strconv.Itoa(px)
+	}
+
+	return points, nil
+}
+
+// androidScreenSize returns the effective display size reported by 'wm size'.
+func androidScreenSize(udid string) (width, height int, err error) {
+	out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "wm", "size")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read screen size: %w", err)
+	}
+
+	w, h, ok := strings.Cut(lastWMValue(strings.TrimSpace(string(out))), "x")
+	width, errW := strconv.Atoi(w)
+	height, errH := strconv.Atoi(h)
+	if !ok || errW != nil || errH != nil {
+		return 0, 0, fmt.Errorf("failed to parse screen size from %q", strings.TrimSpace(string(out))) //nolint:err113
+	}
+
+	return width, height, nil
+}
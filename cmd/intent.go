@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultIntentAction is the action 'sim open' starts activities with.
+const defaultIntentAction = "android.intent.action.VIEW"
+
+// intentExtraTypes maps --extra types to their 'am start' options.
+var intentExtraTypes = map[string]string{
+	"string": "--es",
+	"int":    "--ei",
+	"long":   "--el",
+	"float":  "--ef",
+	"double": "--ed",
+	"bool":   "--ez",
+	"uri":    "--eu",
+}
+
+// intentFlagNames are the Intent.FLAG_ACTIVITY_* names accepted by --flags.
+var intentFlagNames = map[string]uint32{
+	"NEW_TASK":         0x10000000,
+	"CLEAR_TOP":        0x04000000,
+	"SINGLE_TOP":       0x20000000,
+	"CLEAR_TASK":       0x00008000,
+	"NO_HISTORY":       0x40000000,
+	"NO_ANIMATION":     0x00010000,
+	"REORDER_TO_FRONT": 0x00020000,
+	"MULTIPLE_TASK":    0x08000000,
+}
+
+// amStartTimePattern matches the timing lines printed by 'am start -W'.
+var amStartTimePattern = regexp.MustCompile(`(?m)^(TotalTime|WaitTime|LaunchState):\s*(\S+)`)
+
+// AndroidIntent holds the options used to start an activity for a URL on Android.
+// The zero value opens the URL with the VIEW action and lets Android pick the handler.
+type AndroidIntent struct {
+	Action     string
+	Package    string // Restrict resolution to this package.
+	Component  string // Explicit activity, e.g. com.example/.MainActivity.
+	Categories []string
+	Extras     []string // key:type=value, see ParseIntentExtra.
+	Flags      string   // Number (0x10000000) or names (NEW_TASK|CLEAR_TOP).
+	Wait       bool     // Wait for the launch to complete and report its timing.
+}
+
+// IsZero reports whether no intent option was set.
+func (i AndroidIntent) IsZero() bool {
+	return i.Action == "" && i.Package == "" && i.Component == "" && len(i.Categories) == 0 &&
+		len(i.Extras) == 0 && i.Flags == "" && !i.Wait
+}
+
+// ParseIntentExtra converts key:type=value into 'am start' arguments. The type is one of
+// string, int, long, float, double, bool or uri and may be omitted for strings.
+func ParseIntentExtra(extra string) ([]string, error) {
+	spec, value, ok := strings.Cut(extra, "=")
+	if !ok {
+		return nil, fmt.Errorf("%w: extra %q must be key:type=value", ErrInvalidIntent, extra)
+	}
+
+	key, typ, hasType := strings.Cut(spec, ":")
+	if !hasType {
+		typ = "string"
+	}
+	if key == "" {
+		return nil, fmt.Errorf("%w: extra %q has no key", ErrInvalidIntent, extra)
+	}
+
+	opt, ok := intentExtraTypes[strings.ToLower(typ)]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown extra type %q (use string, int, long, float, double, bool or uri)", ErrInvalidIntent, typ)
+	}
+
+	var err error
+	switch opt {
+	case "--ei", "--el":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "--ef", "--ed":
+		_, err = strconv.ParseFloat(value, 64)
+	case "--ez":
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: extra %s is not a valid %s: %q", ErrInvalidIntent, key, typ, value)
+	}
+
+	return []string{opt, shellQuote(key), shellQuote(value)}, nil
+}
+
+// ParseIntentFlags converts a number or '|'-separated FLAG_ACTIVITY_* names into the
+// hexadecimal value 'am start -f' expects.
+func ParseIntentFlags(flags string) (string, error) {
+	if n, err := strconv.ParseUint(flags, 0, 32); err == nil {
+		return fmt.Sprintf("0x%08x", n), nil
+	}
+
+	var value uint32
+	for _, name := range strings.Split(flags, "|") {
+		name = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(name)), "FLAG_ACTIVITY_")
+		flag, ok := intentFlagNames[name]
+		if !ok {
+			return "", fmt.Errorf("%w: unknown flag %q", ErrInvalidIntent, name)
+		}
+		value |= flag
+	}
+
+	return fmt.Sprintf("0x%08x", value), nil
+}
+
+// StartArgs returns the 'adb shell' arguments that start an activity for url.
+func (i AndroidIntent) StartArgs(url string) ([]string, error) {
+	args := []string{"am", "start"}
+	if i.Wait {
+		args = append(args, "-W")
+	}
+
+	action := i.Action
+	if action == "" {
+		action = defaultIntentAction
+	}
+	args = append(args, "-a", shellQuote(action))
+
+	if url != "" {
+		args = append(args, "-d", shellQuote(url))
+	}
+	for _, category := range i.Categories {
+		args = append(args, "-c", shellQuote(category))
+	}
+	if i.Flags != "" {
+		flags, err := ParseIntentFlags(i.Flags)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "-f", flags)
+	}
+	for _, extra := range i.Extras {
+		extraArgs, err := ParseIntentExtra(extra)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, extraArgs...)
+	}
+
+	if i.Package != "" {
+		if err := validateAppID(i.Package); err != nil {
+			return nil, err
+		}
+		args = append(args, "-p", shellQuote(i.Package))
+	}
+	if i.Component != "" {
+		if !strings.Contains(i.Component, "/") {
+			return nil, fmt.Errorf("%w: component %q must be package/activity", ErrInvalidIntent, i.Component)
+		}
+		args = append(args, "-n", shellQuote(i.Component))
+	}
+
+	return args, nil
+}
+
+// parseAmStartOutput returns the error 'am start' reported, if any, and the launch
+// timing printed by -W (e.g. "312 ms (COLD)").
+func parseAmStartOutput(output string) (timing string, err error) {
+	for _, line := range strings.Split(output, "\n") {
+		if msg, ok := strings.CutPrefix(strings.TrimSpace(line), "Error:"); ok {
+			return "", fmt.Errorf("%w: %s", ErrInvalidIntent, strings.TrimSpace(msg))
+		}
+	}
+
+	values := map[string]string{}
+	for _, m := range amStartTimePattern.FindAllStringSubmatch(output, -1) {
+		values[m[1]] = m[2]
+	}
+
+	total := values["TotalTime"]
+	if total == "" {
+		total = values["WaitTime"]
+	}
+	if total == "" {
+		return "", nil
+	}
+
+	timing = total + " ms"
+	if state := values["LaunchState"]; state != "" {
+		timing += " (" + state + ")"
+	}
+
+	return timing, nil
+}
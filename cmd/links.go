@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// associatedDomainsKey is the entitlement listing an iOS app's associated domains.
+	associatedDomainsKey = "com.apple.developer.associated-domains"
+	// applicationIdentifierKey is the entitlement holding TEAMID.bundle-id.
+	applicationIdentifierKey = "application-identifier"
+	// appLinksServicePrefix marks associated domains used for universal links.
+	appLinksServicePrefix = "applinks:"
+
+	// appLinkVerifyTimeout bounds how long to wait for Android to finish re-verifying a package.
+	appLinkVerifyTimeout = 15 * time.Second
+	// aasaFetchTimeout bounds fetching an apple-app-site-association file over HTTP.
+	aasaFetchTimeout = 10 * time.Second
+)
+
+// androidVerifiedLinkStates are the domain verification states in which Android opens
+// links in the app instead of the browser.
+var androidVerifiedLinkStates = []string{"verified", "approved", "migrated", "restored", "system_configured"}
+
+var linksCmd = &cobra.Command{
+	Use:   "links",
+	Short: "Diagnose App Links and Universal Links",
+	Long: `Diagnose why a link opens in the browser instead of the app.
+
+Android checks the domain verification state of App Links. iOS checks the
+associated-domains entitlement of the installed app and, optionally, an
+apple-app-site-association file.`,
+}
+
+var linksVerifyCmd = &cobra.Command{
+	Use:   "verify [device-name-or-udid] <bundle-id-or-package> <domain>",
+	Short: "Check that a domain opens links in the app",
+	Long: `Check that links on a domain open in the app.
+
+Android (12+) re-runs domain verification with 'pm verify-app-links --re-verify' and
+shows the state of every domain from 'pm get-app-links'.
+
+iOS reads the installed app's associated-domains entitlement with codesign. Pass
+--aasa with a local file or URL to check an apple-app-site-association file against
+the app's identifier.
+
+Examples:
+  sim links verify com.example.app example.com
+  sim links verify "iPhone 15" com.example.app example.com --aasa ./apple-app-site-association
+  sim links verify com.example.app example.com --aasa http://localhost:8080/.well-known/apple-app-site-association`,
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 3 {
+			deviceID = args[0]
+			args = args[1:]
+		}
+
+		aasa, _ := cmd.Flags().GetString("aasa")
+
+		return VerifyAppLinks(deviceID, args[0], args[1], aasa)
+	},
+}
+
+func init() {
+	linksVerifyCmd.Flags().String("aasa", "", "iOS: apple-app-site-association file or URL to check")
+	linksCmd.AddCommand(linksVerifyCmd)
+}
+
+// VerifyAppLinks checks that links on domain open in appID on the given device and
+// returns ErrLinkNotVerified with the reason when they do not.
+// Pass an empty deviceID to use the first running device.
+func VerifyAppLinks(deviceID, appID, domain, aasa string) error {
+	if err := validateAppID(appID); err != nil {
+		return err
+	}
+	// Accept a pasted link as well as a bare host.
+	domain = strings.TrimPrefix(strings.TrimPrefix(domain, "https://"), "http://")
+	domain, _, _ = strings.Cut(strings.ToLower(domain), "/")
+
+	udid, _, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if isAndroid {
+		return verifyAndroidAppLinks(udid, appID, domain)
+	}
+
+	return verifyIOSUniversalLinks(udid, appID, domain, aasa)
+}
+
+// ParseAndroidAppLinks returns the domain verification states of pkg from 'pm get-app-links' output.
+func ParseAndroidAppLinks(output, pkg string) map[string]string {
+	states := map[string]string{}
+	inPackage, inDomains := false, false
+
+	for line := range strings.SplitSeq(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasSuffix(trimmed, ":") && !strings.Contains(trimmed, " "):
+			// A bare "com.example.app:" line starts the next package.
+			inPackage = strings.TrimSuffix(trimmed, ":") == pkg
+			inDomains = false
+		case inPackage && trimmed == "Domain verification state:":
+			inDomains = true
+		case inPackage && inDomains:
+			domain, state, ok := strings.Cut(trimmed, ":")
+			if !ok || strings.Contains(domain, " ") {
+				inDomains = false
+				continue
+			}
+			states[strings.ToLower(domain)] = strings.TrimSpace(state)
+		}
+	}
+
+	return states
+}
+
+// verifyAndroidAppLinks re-verifies pkg and reports the state of domain.
+func verifyAndroidAppLinks(udid, pkg, domain string) error {
+	var states map[string]string
+	err := RunSpinner(fmt.Sprintf("Verifying App Links for %s...", pkg), func() error {
+		if out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "pm", "verify-app-links", "--re-verify", pkg); err != nil {
+			return fmt.Errorf("pm verify-app-links failed (App Links verification needs Android 12+): %w\nOutput: %s", err, string(out))
+		}
+
+		// Verification runs asynchronously; poll until the domain leaves the "none" state.
+		deadline := time.Now().Add(appLinkVerifyTimeout)
+		for {
+			out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "pm", "get-app-links", pkg)
+			if err != nil {
+				return fmt.Errorf("pm get-app-links failed: %w", err)
+			}
+			states = ParseAndroidAppLinks(string(out), pkg)
+
+			if state, ok := states[domain]; !ok || state != "none" || time.Now().After(deadline) {
+				return nil
+			}
+			time.Sleep(time.Second)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(states) == 0 {
+		return fmt.Errorf("%w: %s declares no App Links; add an intent filter with android:autoVerify=\"true\" "+
+			"and <data android:scheme=\"https\" android:host=\"%s\"/>", ErrLinkNotVerified, pkg, domain)
+	}
+
+	rows := make([][]string, 0, len(states))
+	for _, d := range slices.Sorted(maps.Keys(states)) {
+		rows = append(rows, []string{d, states[d]})
+	}
+	RenderTable([]string{"Domain", "State"}, rows)
+
+	state, ok := states[domain]
+	switch {
+	case !ok:
+		return fmt.Errorf("%w: %s is not declared by %s; add it to an autoVerify intent filter", ErrLinkNotVerified, domain, pkg)
+	case slices.Contains(androidVerifiedLinkStates, state):
+		PrintSuccess(fmt.Sprintf("Links to %s open in %s (%s).", domain, pkg, state))
+		return nil
+	case state == "none":
+		return fmt.Errorf("%w: %s is still pending verification; check that the emulator can reach "+
+			"https://%s/.well-known/assetlinks.json", ErrLinkNotVerified, domain, domain)
+	default:
+		return fmt.Errorf("%w: %s is %s; check https://%s/.well-known/assetlinks.json lists %s and its signing certificate",
+			ErrLinkNotVerified, domain, state, domain, pkg)
+	}
+}
+
+// verifyIOSUniversalLinks checks the associated-domains entitlement of appID and,
+// when aasa is set, the apple-app-site-association file.
+func verifyIOSUniversalLinks(udid, appID, domain, aasa string) error {
+	if runtime.GOOS != DarwinOS {
+		return ErrIOSMacOnly
+	}
+
+	bundle, err := iosAppContainer(udid, appID, "app")
+	if err != nil {
+		return err
+	}
+
+	out, err := packageExecutor.Output(CmdCodesign, "-d", "--entitlements", "-", "--xml", bundle)
+	if err != nil {
+		return fmt.Errorf("failed to read entitlements of %s: %w", appID, err)
+	}
+
+	entitlements, err := ParseEntitlements(out)
+	if err != nil {
+		return err
+	}
+
+	domains := entitlements[associatedDomainsKey]
+	var matched string
+	rows := make([][]string, 0, len(domains))
+	for _, entry := range domains {
+		match := ""
+		if AssociatedDomainMatches(entry, domain) {
+			match = "✓"
+			if matched == "" {
+				matched = entry
+			}
+		}
+		rows = append(rows, []string{entry, match})
+	}
+	if len(rows) > 0 {
+		RenderTable([]string{"Associated Domain", domain}, rows)
+	}
+
+	if matched == "" {
+		return fmt.Errorf("%w: the %s entitlement of %s has no applinks:%s entry", ErrLinkNotVerified,
+			associatedDomainsKey, appID, domain)
+	}
+	PrintSuccess(fmt.Sprintf("%s is entitled to open links to %s (%s).", appID, domain, matched))
+
+	if aasa == "" {
+		PrintInfo("Pass --aasa to check the apple-app-site-association file as well.")
+		return nil
+	}
+
+	content, err := readAASA(aasa)
+	if err != nil {
+		return err
+	}
+
+	appIdentifier := appID
+	if ids := entitlements[applicationIdentifierKey]; len(ids) > 0 {
+		appIdentifier = ids[0]
+	}
+	if err := CheckAASA(content, appIdentifier); err != nil {
+		return err
+	}
+	PrintSuccess(fmt.Sprintf("apple-app-site-association lists %s.", appIdentifier))
+
+	return nil
+}
+
+// ParseEntitlements extracts the string and string-array values of an entitlements
+// plist (as printed by 'codesign -d --entitlements - --xml'), keyed by entitlement.
+func ParseEntitlements(plist []byte) (map[string][]string, error) {
+	// codesign may print an "Executable=" line before the plist.
+	if i := bytes.Index(plist, []byte("<?xml")); i > 0 {
+		plist = plist[i:]
+	}
+
+	entitlements := map[string][]string{}
+	dec := xml.NewDecoder(bytes.NewReader(plist))
+	var key, text string
+	depth := 0
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse entitlements: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			text = ""
+		case xml.CharData:
+			text += string(t)
+		case xml.EndElement:
+			depth--
+			switch {
+			case t.Name.Local == "key" && depth == 2:
+				key = strings.TrimSpace(text)
+			case t.Name.Local == "string" && key != "":
+				entitlements[key] = append(entitlements[key], strings.TrimSpace(text))
+			}
+		}
+	}
+
+	return entitlements, nil
+}
+
+// AssociatedDomainMatches reports whether an associated-domains entry such as
+// "applinks:example.com", "applinks:*.example.com" or "applinks:example.com?mode=developer"
+// covers universal links to domain.
+func AssociatedDomainMatches(entry, domain string) bool {
+	host, ok := strings.CutPrefix(entry, appLinksServicePrefix)
+	if !ok {
+		return false
+	}
+	host, _, _ = strings.Cut(host, "?")
+	host = strings.ToLower(host)
+
+	if suffix, ok := strings.CutPrefix(host, "*."); ok {
+		return domain == suffix || strings.HasSuffix(domain, "."+suffix)
+	}
+
+	return host == domain
+}
+
+// CheckAASA checks that an apple-app-site-association file routes links to appID
+// (TEAMID.bundle-id, or a bundle ID when the team is unknown). Both the legacy
+// "appID"/"paths" and the current "appIDs"/"components" formats are accepted.
+func CheckAASA(content []byte, appID string) error {
+	var aasa struct {
+		AppLinks *struct {
+			Details []struct {
+				AppID  string   `json:"appID"`
+				AppIDs []string `json:"appIDs"`
+			} `json:"details"`
+		} `json:"applinks"`
+	}
+	if err := json.Unmarshal(content, &aasa); err != nil {
+		return fmt.Errorf("%w: apple-app-site-association is not valid JSON: %w", ErrLinkNotVerified, err)
+	}
+	if aasa.AppLinks == nil {
+		return fmt.Errorf("%w: apple-app-site-association has no applinks section", ErrLinkNotVerified)
+	}
+
+	var listed []string
+	for _, detail := range aasa.AppLinks.Details {
+		ids := detail.AppIDs
+		if detail.AppID != "" {
+			ids = append(ids, detail.AppID)
+		}
+		for _, id := range ids {
+			// Without the team ID, match on the bundle ID part of TEAMID.bundle-id.
+			if id == appID || strings.HasSuffix(id, "."+appID) {
+				return nil
+			}
+			listed = append(listed, id)
+		}
+	}
+
+	if len(listed) == 0 {
+		return fmt.Errorf("%w: apple-app-site-association lists no app IDs", ErrLinkNotVerified)
+	}
+
+	return fmt.Errorf("%w: apple-app-site-association lists %s but not %s", ErrLinkNotVerified,
+		strings.Join(listed, ", "), appID)
+}
+
+// readAASA reads an apple-app-site-association file from a path or an http(s) URL.
+func readAASA(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read apple-app-site-association: %w", err)
+		}
+
+		return content, nil
+	}
+
+	client := &http.Client{Timeout: aasaFetchTimeout}
+	resp, err := client.Get(source) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch apple-app-site-association: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: fetching %s returned %s", ErrLinkNotVerified, source, resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "json") {
+		PrintInfo(fmt.Sprintf("Warning: %s is served as %s; Apple expects application/json.", source, ct))
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// androidSetTimeZoneCall is the IAlarmManager transaction code of setTimeZone.
+const androidSetTimeZoneCall = "3"
+
+// localePattern matches locale identifiers such as fr, fr_FR, pt-BR or zh_Hans_CN.
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}([_-][a-zA-Z0-9]{2,8})*$`)
+
+// LocaleSettings holds the language, region and timezone configuration of a device.
+type LocaleSettings struct {
+	Locale   string // e.g. "fr_FR".
+	Language string // e.g. "fr". Defaults to the language part of Locale.
+	Timezone string // IANA name, e.g. "Europe/Paris". Empty leaves the timezone unchanged.
+}
+
+var localeCmd = &cobra.Command{
+	Use:   "locale",
+	Short: "Switch the device language, region and timezone",
+	Long: `Change the system locale of a running device and restart it so apps pick up the change.
+
+iOS simulators get AppleLanguages and AppleLocale written to their global preferences.
+iOS simulators always use the host's timezone, so --timezone is Android-only.
+Android emulators need root access ('adb root'), which Google APIs images allow and
+Google Play images do not.`,
+}
+
+var localeSetCmd = &cobra.Command{
+	Use:               "set [device-name-or-udid] <locale>",
+	Short:             "Set the locale and restart the device",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, locale := splitDeviceArg(args)
+
+		s := LocaleSettings{Locale: locale}
+		s.Language, _ = cmd.Flags().GetString("language")
+		s.Timezone, _ = cmd.Flags().GetString("timezone")
+
+		return SetLocale(deviceID, s)
+	},
+}
+
+var localeGetCmd = &cobra.Command{
+	Use:               "get [device-name-or-udid]",
+	Short:             "Show the current locale settings",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		udid, name, isAndroid, err := findSettingsDevice(deviceID)
+		if err != nil {
+			return err
+		}
+
+		s := GetLocaleSettings(udid, isAndroid)
+		RenderTable([]string{"Device", "Locale", "Language", "Timezone"},
+			[][]string{{name, s.Locale, s.Language, s.Timezone}})
+
+		return nil
+	},
+}
+
+func init() {
+	localeSetCmd.Flags().String("language", "", "Preferred language (defaults to the locale's language)")
+	localeSetCmd.Flags().String("timezone", "", "IANA timezone, e.g. Europe/Paris (Android only)")
+
+	localeCmd.AddCommand(localeSetCmd)
+	localeCmd.AddCommand(localeGetCmd)
+}
+
+// Normalize validates s and fills in the language from the locale when it is not set.
+func (s LocaleSettings) Normalize() (LocaleSettings, error) {
+	if !localePattern.MatchString(s.Locale) {
+		return s, fmt.Errorf("%w: %q", ErrInvalidLocale, s.Locale)
+	}
+	s.Locale = strings.ReplaceAll(s.Locale, "-", "_")
+
+	if s.Language == "" {
+		s.Language, _, _ = strings.Cut(s.Locale, "_")
+	}
+	if !localePattern.MatchString(s.Language) {
+		return s, fmt.Errorf("%w: language %q", ErrInvalidLocale, s.Language)
+	}
+
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "Local" {
+			return s, fmt.Errorf("%w: unknown timezone %q", ErrInvalidLocale, s.Timezone)
+		}
+	}
+
+	return s, nil
+}
+
+// AndroidLocaleTag returns the BCP 47 tag Android stores in persist.sys.locale:
+// the preferred language combined with the region of the locale (e.g. "fr-FR").
+func (s LocaleSettings) AndroidLocaleTag() string {
+	tag := s.Language
+	if _, region, ok := strings.Cut(s.Locale, "_"); ok {
+		tag += "-" + strings.ReplaceAll(region, "_", "-")
+	}
+
+	return tag
+}
+
+// SetLocale applies s to the device, restarts it and verifies the new values.
+// Pass an empty deviceID to use the first running device.
+func SetLocale(deviceID string, s LocaleSettings) error {
+	s, err := s.Normalize()
+	if err != nil {
+		return err
+	}
+
+	udid, name, isAndroid, err := findSettingsDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if !isAndroid && s.Timezone != "" {
+		PrintInfo("iOS simulators use the host's timezone; --timezone is ignored.")
+		s.Timezone = ""
+	}
+
+	err = RunSpinner(fmt.Sprintf("Switching '%s' to %s and restarting...", name, s.Locale), func() error {
+		if isAndroid {
+			udid, err = applyAndroidLocale(udid, name, s)
+		} else {
+			err = applyIOSLocale(udid, s)
+		}
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := verifyLocale(GetLocaleSettings(udid, isAndroid), s, isAndroid); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("'%s' is now using %s (language %s", name, s.Locale, s.Language)
+	if s.Timezone != "" {
+		msg += ", timezone " + s.Timezone
+	}
+	PrintSuccess(msg + ").")
+
+	return nil
+}
+
+func applyIOSLocale(udid string, s LocaleSettings) error {
+	writes := [][]string{
+		{"AppleLanguages", "-array", s.Language},
+		{"AppleLocale", "-string", s.Locale},
+	}
+	for _, w := range writes {
+		args := append([]string{CmdSimctl, "spawn", udid, "defaults", "write", "-g"}, w...)
+		if err := packageExecutor.Run(CmdXCrun, args...); err != nil {
+			return fmt.Errorf("failed to write %s: %w", w[0], err)
+		}
+	}
+
+	if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "shutdown", udid); err != nil {
+		return fmt.Errorf("failed to shut down iOS simulator: %w", err)
+	}
+	if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "bootstatus", udid, "-b"); err != nil {
+		return fmt.Errorf("failed to boot iOS simulator: %w", err)
+	}
+
+	return nil
+}
+
+// applyAndroidLocale sets the locale and timezone as root, reboots the emulator and
+// returns its serial once it has booted again.
+func applyAndroidLocale(udid, avdName string, s LocaleSettings) (string, error) {
+	if !androidRoot(udid) {
+		return "", fmt.Errorf("%w: 'adb root' is not available; use a Google APIs system image", ErrLocaleNotApplied)
+	}
+
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "setprop", "persist.sys.locale", s.AndroidLocaleTag()); err != nil {
+		return "", fmt.Errorf("failed to set Android locale: %w", err)
+	}
+	if s.Timezone != "" {
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "service", "call", "alarm",
+			androidSetTimeZoneCall, "s16", s.Timezone); err != nil {
+			return "", fmt.Errorf("failed to set Android timezone: %w", err)
+		}
+	}
+
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "reboot"); err != nil {
+		return "", fmt.Errorf("failed to reboot Android emulator: %w", err)
+	}
+
+	return waitForAndroidBoot(avdName)
+}
+
+// GetLocaleSettings reads the current locale configuration from a running device.
+// Values the platform cannot report are left empty.
+func GetLocaleSettings(udid string, isAndroid bool) LocaleSettings {
+	var s LocaleSettings
+
+	if isAndroid {
+		tag := androidProp(udid, "persist.sys.locale")
+		if tag == "" {
+			tag = androidProp(udid, "ro.product.locale")
+		}
+		s.Language, _, _ = strings.Cut(tag, "-")
+		s.Locale = strings.ReplaceAll(tag, "-", "_")
+		s.Timezone = androidProp(udid, "persist.sys.timezone")
+
+		return s
+	}
+
+	if out, err := packageExecutor.Output(CmdXCrun, CmdSimctl, "spawn", udid, "defaults", "read", "-g", "AppleLocale"); err == nil {
+		s.Locale = strings.TrimSpace(string(out))
+	}
+	// AppleLanguages is printed as a plist array: ( fr, "en-US" ).
+	if out, err := packageExecutor.Output(CmdXCrun, CmdSimctl, "spawn", udid, "defaults", "read", "-g", "AppleLanguages"); err == nil {
+		for _, field := range strings.FieldsFunc(string(out), func(r rune) bool {
+			return strings.ContainsRune("(),\" \n\t", r)
+		}) {
+			s.Language = field
+			break
+		}
+	}
+
+	return s
+}
+
+// verifyLocale checks that the values read back from the device match what was requested.
+func verifyLocale(got, want LocaleSettings, isAndroid bool) error {
+	wantLocale := want.Locale
+	if isAndroid {
+		wantLocale = strings.ReplaceAll(want.AndroidLocaleTag(), "-", "_")
+	}
+
+	var mismatches []string
+	if !strings.EqualFold(got.Locale, wantLocale) {
+		mismatches = append(mismatches, fmt.Sprintf("locale is %q, expected %q", got.Locale, wantLocale))
+	}
+	if !strings.EqualFold(got.Language, want.Language) {
+		mismatches = append(mismatches, fmt.Sprintf("language is %q, expected %q", got.Language, want.Language))
+	}
+	if want.Timezone != "" && got.Timezone != want.Timezone {
+		mismatches = append(mismatches, fmt.Sprintf("timezone is %q, expected %q", got.Timezone, want.Timezone))
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w: %s", ErrLocaleNotApplied, strings.Join(mismatches, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultRouteSpeed is the travel speed in m/s for routes without timestamps (simctl's default).
+	defaultRouteSpeed = 20.0
+	// androidRouteStep is how often a new geo fix is sent during Android route playback.
+	androidRouteStep = time.Second
+)
+
+var locationCmd = &cobra.Command{
+	Use:     "location",
+	Aliases: []string{"loc", "gps"},
+	Short:   "Simulate the device location",
+	Long: `Set a fixed location or play back a GPX/KML route on a running device.
+
+iOS simulators use 'simctl location'. Android emulators receive 'adb emu geo fix'
+updates; route playback keeps running until the route ends or Ctrl+C is pressed.
+
+Use '--' before negative coordinates so they are not parsed as flags:
+  sim location set -- 37.3349 -122.0090`,
+}
+
+var locationSetCmd = &cobra.Command{
+	Use:               "set [device-name-or-udid] <lat> <lon>",
+	Short:             "Set a fixed location",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, lat, lon, err := parseLocationArgs(args)
+		if err != nil {
+			return err
+		}
+
+		return SetLocation(deviceID, lat, lon)
+	},
+}
+
+var locationClearCmd = &cobra.Command{
+	Use:               "clear [device-name-or-udid]",
+	Short:             "Clear the simulated location",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		return ClearLocation(deviceID)
+	},
+}
+
+var locationPlayCmd = &cobra.Command{
+	Use:   "play [device-name-or-udid] <route.gpx|route.kml>",
+	Short: "Play back a GPX or KML route",
+	Long: `Move the device along the waypoints of a GPX or KML file.
+
+Recorded timestamps set the pace when every waypoint has one; otherwise the device
+travels at --base-speed. --speed scales either pace (e.g. 2x, 0.5x).`,
+	ValidArgsFunction: validDeviceAndFileArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID, routePath string
+		if len(args) == 1 {
+			routePath = args[0]
+		} else {
+			deviceID = args[0]
+			routePath = args[1]
+		}
+
+		speed, _ := cmd.Flags().GetString("speed")
+		multiplier, err := ParseSpeedMultiplier(speed)
+		if err != nil {
+			return err
+		}
+
+		baseSpeed, _ := cmd.Flags().GetFloat64("base-speed")
+		if baseSpeed <= 0 {
+			return fmt.Errorf("%w: --base-speed must be positive", ErrInvalidSpeed)
+		}
+
+		return PlayRoute(deviceID, routePath, baseSpeed, multiplier)
+	},
+}
+
+func init() {
+	locationPlayCmd.Flags().String("speed", "1x", "Playback speed multiplier (e.g. 2x, 0.5x)")
+	locationPlayCmd.Flags().Float64("base-speed", defaultRouteSpeed, "Travel speed in m/s for routes without timestamps")
+
+	locationCmd.AddCommand(locationSetCmd)
+	locationCmd.AddCommand(locationClearCmd)
+	locationCmd.AddCommand(locationPlayCmd)
+}
+
+// parseLocationArgs resolves [device] <lat> <lon>, also accepting "<lat>,<lon>" as one argument.
+func parseLocationArgs(args []string) (deviceID string, lat, lon float64, err error) {
+	coords := args
+	if n := len(args); n > 0 && strings.Contains(args[n-1], ",") {
+		coords = strings.SplitN(args[n-1], ",", 2)
+		if n == 2 {
+			deviceID = args[0]
+		} else if n > 2 {
+			return "", 0, 0, fmt.Errorf("%w: too many arguments", ErrInvalidCoordinate)
+		}
+	} else {
+		switch n {
+		case 2:
+		case 3:
+			deviceID = args[0]
+			coords = args[1:]
+		default:
+			return "", 0, 0, fmt.Errorf("%w: expected <lat> <lon>", ErrInvalidCoordinate)
+		}
+	}
+
+	lat, errLat := strconv.ParseFloat(strings.TrimSpace(coords[0]), 64)
+	lon, errLon := strconv.ParseFloat(strings.TrimSpace(coords[1]), 64)
+	if errLat != nil || errLon != nil {
+		return "", 0, 0, fmt.Errorf("%w: %s", ErrInvalidCoordinate, strings.Join(coords, ","))
+	}
+
+	return deviceID, lat, lon, ValidateCoordinate(lat, lon)
+}
+
+// formatCoordinate renders a coordinate pair the way simctl expects ("lat,lon").
+func formatCoordinate(lat, lon float64) string {
+	return strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lon, 'f', -1, 64)
+}
+
+// sendAndroidGeoFix moves an Android emulator to lat/lon. Note the console takes longitude first.
+func sendAndroidGeoFix(udid string, lat, lon float64) error {
+	return packageExecutor.Run(CmdAdb, "-s", udid, "emu", "geo", "fix",
+		strconv.FormatFloat(lon, 'f', -1, 64), strconv.FormatFloat(lat, 'f', -1, 64))
+}
+
+// SetLocation sets a fixed location on the device. Pass an empty deviceID to use the first running device.
+func SetLocation(deviceID string, lat, lon float64) error {
+	if err := ValidateCoordinate(lat, lon); err != nil {
+		return err
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if isAndroid {
+		if err := sendAndroidGeoFix(udid, lat, lon); err != nil {
+			return fmt.Errorf("failed to set location on Android emulator: %w", err)
+		}
+	} else {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+		if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "location", udid, "set", formatCoordinate(lat, lon)); err != nil {
+			return fmt.Errorf("failed to set location on iOS simulator: %w", err)
+		}
+	}
+
+	PrintSuccess(fmt.Sprintf("Location of '%s' set to %s.", name, formatCoordinate(lat, lon)))
+
+	return nil
+}
+
+// ClearLocation stops any simulated location. Pass an empty deviceID to use the first running device.
+func ClearLocation(deviceID string) error {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if isAndroid {
+		PrintInfo(fmt.Sprintf("Android emulators keep their last geo fix; '%s' will stay at its current location.", name))
+		PrintInfo("Stop any running 'sim location play' with Ctrl+C, or set a new location from the emulator's Extended Controls.")
+
+		return nil
+	}
+
+	if runtime.GOOS != DarwinOS {
+		return ErrIOSMacOnly
+	}
+	if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "location", udid, "clear"); err != nil {
+		return fmt.Errorf("failed to clear location on iOS simulator: %w", err)
+	}
+
+	PrintSuccess(fmt.Sprintf("Simulated location cleared on '%s'.", name))
+
+	return nil
+}
+
+// PlayRoute moves the device along the waypoints in routePath.
+// baseSpeed (m/s) is used for routes without timestamps; multiplier scales the pace.
+func PlayRoute(deviceID, routePath string, baseSpeed, multiplier float64) error {
+	points, err := ParseRouteFile(routePath)
+	if err != nil {
+		return err
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+
+		speed := RouteAverageSpeed(points, baseSpeed, multiplier)
+		args := []string{CmdSimctl, "location", udid, "start", fmt.Sprintf("--speed=%.2f", speed)}
+		for _, p := range points {
+			args = append(args, formatCoordinate(p.Lat, p.Lon))
+		}
+
+		if err := packageExecutor.Run(CmdXCrun, args...); err != nil {
+			return fmt.Errorf("failed to start route on iOS simulator: %w", err)
+		}
+
+		PrintSuccess(fmt.Sprintf("Playing %d waypoints on '%s' at %.1f m/s. Use 'sim location clear' to stop.", len(points), name, speed))
+
+		return nil
+	}
+
+	samples := InterpolateRoute(points, baseSpeed, multiplier, androidRouteStep)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	go func() {
+		<-sigChan
+		PrintInfo("\nStopping route playback...")
+		cancel()
+	}()
+
+	total := samples[len(samples)-1].Offset
+	PrintInfo(fmt.Sprintf("Playing %d waypoints on '%s' over %s (Press Ctrl+C to stop)...", len(points), name, total.Round(time.Second)))
+
+	if err := playAndroidSamples(ctx, udid, samples); err != nil {
+		return err
+	}
+
+	PrintSuccess("Route playback finished.")
+
+	return nil
+}
+
+// playAndroidSamples sends each sample as a geo fix at its offset from the start time.
+func playAndroidSamples(ctx context.Context, udid string, samples []RouteSample) error {
+	start := time.Now()
+
+	for _, s := range samples {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(start.Add(s.Offset))):
+		}
+
+		if err := sendAndroidGeoFix(udid, s.Lat, s.Lon); err != nil {
+			return fmt.Errorf("failed to update location on Android emulator: %w", err)
+		}
+	}
+
+	return nil
+}
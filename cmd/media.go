@@ -16,6 +16,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// statusBarSettleDelay is how long to wait after overriding the status bar before capturing.
+const statusBarSettleDelay = 500 * time.Millisecond
+
 // capturer is implemented by any device that can take screenshots and record video.
 type capturer interface {
 	Screenshot(outputFile string) (string, error)
@@ -102,10 +105,9 @@ func (e *androidEmulator) runADB(args ...string) error {
 	cmdArgs := make([]string, 0, 2+len(args))
 	cmdArgs = append(cmdArgs, "-s", e.udid)
 	cmdArgs = append(cmdArgs, args...)
-	cmd := exec.Command(CmdAdb, cmdArgs...)
 
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("adb command failed: %w\nOutput: %s", err, string(output))
+	if err := packageExecutor.Run(CmdAdb, cmdArgs...); err != nil {
+		return fmt.Errorf("adb command failed: %w", err)
 	}
 
 	return nil
@@ -176,6 +178,18 @@ func (e *androidEmulator) GetName() string {
 	return e.name
 }
 
+// capturerTarget returns the device serial/UDID behind a capturer and whether it is an Android emulator.
+func capturerTarget(c capturer) (udid string, isAndroid bool) {
+	switch d := c.(type) {
+	case *androidEmulator:
+		return d.udid, true
+	case *iOSSimulator:
+		return d.udid, false
+	default:
+		return "", false
+	}
+}
+
 func getCapturer(deviceID string) (capturer, error) {
 	if deviceID == "" {
 		return getActiveDevice()
@@ -327,6 +341,24 @@ var screenshotCmd = &cobra.Command{
 			outputFile = filepath.Join(outputDir, outputFile)
 		}
 
+		if cleanStatusBar, _ := cmd.Flags().GetBool("clean-statusbar"); cleanStatusBar {
+			udid, isAndroid := capturerTarget(c)
+			snapshot, err := snapshotStatusBar(udid, isAndroid)
+			if err != nil {
+				return err
+			}
+			if err := applyStatusBar(udid, isAndroid, CleanStatusBar()); err != nil {
+				return err
+			}
+			defer func() {
+				if err := restoreStatusBar(udid, isAndroid, snapshot); err != nil {
+					PrintInfo(fmt.Sprintf("Warning: could not restore status bar overrides: %v", err))
+				}
+			}()
+			// Give SystemUI/SpringBoard a moment to redraw before capturing.
+			time.Sleep(statusBarSettleDelay)
+		}
+
 		var finalPath string
 		err = RunSpinner(fmt.Sprintf("Taking screenshot of %s...", c.GetName()), func() error {
 			var captureErr error
@@ -337,6 +369,24 @@ var screenshotCmd = &cobra.Command{
 			return err
 		}
 
+		if frame, _ := cmd.Flags().GetBool("frame"); frame {
+			opts, err := frameOptionsFromFlags(cmd, config)
+			if err != nil {
+				return err
+			}
+
+			var used string
+			err = RunSpinner("Framing screenshot...", func() error {
+				var frameErr error
+				used, frameErr = FrameScreenshot(finalPath, frameKey(c), opts)
+				return frameErr
+			})
+			if err != nil {
+				return err
+			}
+			PrintInfo(fmt.Sprintf("Framed with %s.", used))
+		}
+
 		PrintSuccess(fmt.Sprintf("Screenshot saved to: %s", finalPath))
 
 		if shouldCopy, _ := cmd.Flags().GetBool("copy"); shouldCopy {
@@ -351,6 +401,30 @@ var screenshotCmd = &cobra.Command{
 	},
 }
 
+// frameOptionsFromFlags resolves --frame-background and --frame-padding, falling back to
+// the frameBackground and framePadding config values.
+func frameOptionsFromFlags(cmd *cobra.Command, config *Config) (FrameOptions, error) {
+	background, _ := cmd.Flags().GetString("frame-background")
+	if background == "" {
+		background = config.FrameBackground
+	}
+	if background == "" {
+		background = defaultFrameBackground
+	}
+
+	bg, err := ParseFrameBackground(background)
+	if err != nil {
+		return FrameOptions{}, err
+	}
+
+	padding, _ := cmd.Flags().GetInt("frame-padding")
+	if !cmd.Flags().Changed("frame-padding") && config.FramePadding > 0 {
+		padding = config.FramePadding
+	}
+
+	return FrameOptions{Background: bg, Padding: padding}, nil
+}
+
 var recordCmd = &cobra.Command{
 	Use:     "record [device-name-or-udid] [output-file]",
 	Aliases: []string{"rec"},
@@ -0,0 +1,435 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// networkFull is the emulator console value for unthrottled speed.
+	networkFull = "full"
+	// networkNoDelay is the emulator console value for no added latency.
+	networkNoDelay = "none"
+	// defaultFlakyInterval is how long each profile lasts in flaky mode.
+	defaultFlakyInterval = 10 * time.Second
+)
+
+// NetworkProfile describes emulated network conditions for an Android emulator.
+// Speed and Latency take emulator console values: a preset name (e.g. "edge") or
+// "<up>:<down>" kbps / "<min>:<max>" ms ranges.
+type NetworkProfile struct {
+	Speed   string `json:"speed,omitempty"`
+	Latency string `json:"latency,omitempty"`
+	Offline bool   `json:"offline,omitempty"`
+}
+
+// builtinNetworkProfiles are always available; profiles in config take precedence.
+var builtinNetworkProfiles = map[string]NetworkProfile{
+	"edge":    {Speed: "edge", Latency: "edge"},
+	"3g":      {Speed: "umts", Latency: "umts"},
+	"lte":     {Speed: "lte", Latency: networkNoDelay},
+	"full":    {Speed: networkFull, Latency: networkNoDelay},
+	"offline": {Offline: true},
+}
+
+var (
+	networkSpeedPattern   = regexp.MustCompile(`^(gsm|hscsd|gprs|edge|umts|hsdpa|lte|evdo|full|\d+(:\d+)?)$`)
+	networkLatencyPattern = regexp.MustCompile(`^(gprs|edge|umts|none|\d+(:\d+)?)$`)
+)
+
+var networkCmd = &cobra.Command{
+	Use:     "network",
+	Aliases: []string{"net"},
+	Short:   "Simulate network conditions (Android only)",
+	Long: `Throttle, delay or cut the network of a running Android emulator.
+
+Profiles map to 'adb emu network speed/delay'; the offline profile toggles 'svc wifi'
+and 'svc data'. Built-in profiles: edge, 3g, lte, full, offline. Define your own with
+'sim network save' or under "networkProfiles" in the config file.
+
+iOS simulators share the Mac's network; use Apple's Network Link Conditioner instead.`,
+}
+
+var networkProfileCmd = &cobra.Command{
+	Use:               "profile [device-name-or-udid] [profile]",
+	Short:             "Apply a network profile",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID, name string
+		switch len(args) {
+		case 1:
+			name = args[0]
+		case 2:
+			deviceID, name = args[0], args[1]
+		}
+
+		var p NetworkProfile
+		if name != "" {
+			var err error
+			if p, err = ResolveNetworkProfile(name); err != nil {
+				return err
+			}
+		} else {
+			name = "custom"
+			p = builtinNetworkProfiles[networkFull]
+		}
+
+		if cmd.Flags().Changed("speed") || cmd.Flags().Changed("latency") {
+			if p.Offline {
+				return fmt.Errorf("%w: --speed and --latency cannot be combined with an offline profile", ErrInvalidNetworkProfile)
+			}
+			if cmd.Flags().Changed("speed") {
+				p.Speed, _ = cmd.Flags().GetString("speed")
+			}
+			if cmd.Flags().Changed("latency") {
+				p.Latency, _ = cmd.Flags().GetString("latency")
+			}
+		} else if len(args) == 0 {
+			return fmt.Errorf("%w: specify a profile or --speed/--latency", ErrInvalidNetworkProfile)
+		}
+
+		return SetNetworkProfile(deviceID, name, p)
+	},
+}
+
+var networkResetCmd = &cobra.Command{
+	Use:               "reset [device-name-or-udid]",
+	Short:             "Restore full speed, no latency and connectivity",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		return SetNetworkProfile(deviceID, networkFull, builtinNetworkProfiles[networkFull])
+	},
+}
+
+var networkFlakyCmd = &cobra.Command{
+	Use:   "flaky [device-name-or-udid]",
+	Short: "Cycle through network profiles on a schedule",
+	Long: `Switch between network profiles every --interval to reproduce connectivity bugs.
+Runs until --duration elapses or Ctrl+C is pressed, then restores the full network.`,
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		profiles, _ := cmd.Flags().GetStringSlice("profiles")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		duration, _ := cmd.Flags().GetDuration("duration")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigChan)
+
+		go func() {
+			<-sigChan
+			PrintInfo("\nStopping flaky network...")
+			cancel()
+		}()
+
+		return FlakyNetwork(ctx, deviceID, profiles, interval, duration)
+	},
+}
+
+var networkSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save a named network profile to the config file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var p NetworkProfile
+		p.Speed, _ = cmd.Flags().GetString("speed")
+		p.Latency, _ = cmd.Flags().GetString("latency")
+		p.Offline, _ = cmd.Flags().GetBool("offline")
+
+		return SaveNetworkProfile(args[0], p)
+	},
+}
+
+var networkListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available network profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles := NetworkProfiles()
+
+		var rows [][]string
+		for _, name := range slices.Sorted(maps.Keys(profiles)) {
+			p := profiles[name]
+			if p.Offline {
+				rows = append(rows, []string{name, "offline", "-"})
+				continue
+			}
+			rows = append(rows, []string{name, p.Speed, p.Latency})
+		}
+
+		RenderTable([]string{"Profile", "Speed", "Latency"}, rows)
+
+		return nil
+	},
+}
+
+func init() {
+	networkProfileCmd.Flags().String("speed", "", "Speed preset or <up>:<down> kbps")
+	networkProfileCmd.Flags().String("latency", "", "Latency preset or <min>:<max> ms")
+
+	networkFlakyCmd.Flags().StringSlice("profiles", []string{"lte", "edge", "offline"}, "Profiles to cycle through")
+	networkFlakyCmd.Flags().Duration("interval", defaultFlakyInterval, "Time spent in each profile")
+	networkFlakyCmd.Flags().Duration("duration", 0, "Stop after this long (0 runs until Ctrl+C)")
+
+	networkSaveCmd.Flags().String("speed", networkFull, "Speed preset or <up>:<down> kbps")
+	networkSaveCmd.Flags().String("latency", networkNoDelay, "Latency preset or <min>:<max> ms")
+	networkSaveCmd.Flags().Bool("offline", false, "Disable Wi-Fi and mobile data")
+
+	networkCmd.AddCommand(networkProfileCmd)
+	networkCmd.AddCommand(networkResetCmd)
+	networkCmd.AddCommand(networkFlakyCmd)
+	networkCmd.AddCommand(networkSaveCmd)
+	networkCmd.AddCommand(networkListCmd)
+}
+
+// Validate returns an error if the speed or latency is not an emulator console value.
+func (p NetworkProfile) Validate() error {
+	if p.Offline {
+		return nil
+	}
+	if p.Speed != "" && !networkSpeedPattern.MatchString(p.Speed) {
+		return fmt.Errorf("%w: invalid speed %q", ErrInvalidNetworkProfile, p.Speed)
+	}
+	if p.Latency != "" && !networkLatencyPattern.MatchString(p.Latency) {
+		return fmt.Errorf("%w: invalid latency %q", ErrInvalidNetworkProfile, p.Latency)
+	}
+
+	return nil
+}
+
+// NetworkProfiles returns the built-in profiles merged with those defined in config.
+func NetworkProfiles() map[string]NetworkProfile {
+	profiles := maps.Clone(builtinNetworkProfiles)
+
+	if config, err := LoadConfig(); err == nil {
+		maps.Copy(profiles, config.NetworkProfiles)
+	}
+
+	return profiles
+}
+
+// ResolveNetworkProfile looks up a profile by name. An exact match wins; otherwise the name
+// is matched case-insensitively and must not match more than one profile.
+func ResolveNetworkProfile(name string) (NetworkProfile, error) {
+	profiles := NetworkProfiles()
+	if p, ok := profiles[name]; ok {
+		return p, p.Validate()
+	}
+
+	var matches []string
+	for key := range profiles {
+		if strings.EqualFold(key, name) {
+			matches = append(matches, key)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		p := profiles[matches[0]]
+
+		return p, p.Validate()
+	case 0:
+	default:
+		slices.Sort(matches)
+
+		return NetworkProfile{}, fmt.Errorf("%w: %q is ambiguous (matches %s)", ErrInvalidNetworkProfile, name,
+			strings.Join(matches, ", "))
+	}
+
+	return NetworkProfile{}, fmt.Errorf("%w: unknown profile %q (available: %s)", ErrInvalidNetworkProfile, name,
+		strings.Join(slices.Sorted(maps.Keys(profiles)), ", "))
+}
+
+// SaveNetworkProfile stores a named profile in the config file.
+func SaveNetworkProfile(name string, p NetworkProfile) error {
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("%w: invalid profile name %q", ErrInvalidNetworkProfile, name)
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		config = &Config{}
+	}
+	if config.NetworkProfiles == nil {
+		config.NetworkProfiles = map[string]NetworkProfile{}
+	}
+	config.NetworkProfiles[name] = p
+
+	if err := SaveConfig(config); err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Network profile '%s' saved.", name))
+
+	return nil
+}
+
+// findNetworkDevice resolves a running Android emulator; iOS simulators share the host network.
+func findNetworkDevice(deviceID string) (udid, name string, err error) {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return "", "", err
+	}
+	if !isAndroid {
+		return "", "", fmt.Errorf("%w: iOS simulators share the Mac's network; use Network Link Conditioner", ErrUnsupportedPlatform)
+	}
+
+	return udid, name, nil
+}
+
+// SetNetworkProfile applies p to the device. Pass an empty deviceID to use the first running device.
+func SetNetworkProfile(deviceID, name string, p NetworkProfile) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	udid, deviceName, err := findNetworkDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if err := applyNetworkProfile(udid, p); err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Network profile '%s' applied to '%s' (%s).", name, deviceName, p))
+
+	return nil
+}
+
+// FlakyNetwork applies each named profile for interval in turn until ctx is cancelled
+// or duration elapses (0 means no limit). The full network is restored on every exit,
+// including when applying a profile fails.
+func FlakyNetwork(ctx context.Context, deviceID string, names []string, interval, duration time.Duration) (err error) {
+	if len(names) == 0 {
+		return fmt.Errorf("%w: no profiles given", ErrInvalidNetworkProfile)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("%w: interval must be positive", ErrInvalidNetworkProfile)
+	}
+
+	profiles := make([]NetworkProfile, len(names))
+	for i, name := range names {
+		p, err := ResolveNetworkProfile(name)
+		if err != nil {
+			return err
+		}
+		profiles[i] = p
+	}
+
+	udid, deviceName, err := findNetworkDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	PrintInfo(fmt.Sprintf("Cycling '%s' through %s every %s (Press Ctrl+C to stop)...",
+		deviceName, strings.Join(names, ", "), interval))
+
+	defer func() {
+		if restoreErr := applyNetworkProfile(udid, builtinNetworkProfiles[networkFull]); restoreErr != nil {
+			if err == nil {
+				err = restoreErr
+			}
+
+			return
+		}
+		PrintSuccess(fmt.Sprintf("Network restored on '%s'.", deviceName))
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		idx := i % len(profiles)
+		if err := applyNetworkProfile(udid, profiles[idx]); err != nil {
+			return err
+		}
+		PrintInfo(fmt.Sprintf("[%s] %s", time.Now().Format("15:04:05"), names[idx]))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyNetworkProfile sends the console and svc commands for p to an Android emulator.
+func applyNetworkProfile(udid string, p NetworkProfile) error {
+	state := "enable"
+	if p.Offline {
+		state = "disable"
+	}
+	for _, radio := range []string{"wifi", "data"} {
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "svc", radio, state); err != nil {
+			return fmt.Errorf("failed to %s %s: %w", state, radio, err)
+		}
+	}
+	if p.Offline {
+		return nil
+	}
+
+	if p.Speed != "" {
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "emu", "network", "speed", p.Speed); err != nil {
+			return fmt.Errorf("failed to set network speed: %w", err)
+		}
+	}
+	if p.Latency != "" {
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "emu", "network", "delay", p.Latency); err != nil {
+			return fmt.Errorf("failed to set network latency: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// String describes the profile for status messages.
+func (p NetworkProfile) String() string {
+	if p.Offline {
+		return "offline"
+	}
+
+	return fmt.Sprintf("speed %s, latency %s", valueOr(p.Speed, "unchanged"), valueOr(p.Latency, "unchanged"))
+}
+
+func valueOr(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+
+	return v
+}
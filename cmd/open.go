@@ -3,50 +3,152 @@ package cmd
 import (
 	"fmt"
 	"runtime"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 var openCmd = &cobra.Command{
-	Use:     "open [device-name-or-udid] <url>",
+	Use:     "open [device-name-or-udid] <url|@name> [key=value...]",
 	Aliases: []string{"o"},
 	Short:   "Open a deeplink URL on a running iOS simulator or Android emulator",
 	Long: `Open a URL (deeplink) on a running iOS simulator or Android emulator.
 
 If no device is specified, the first booted device is used automatically.
 
+Deeplinks can be saved by name with {param} placeholders and opened with @name,
+passing each parameter as key=value. Values are percent-encoded.
+
 Examples:
   sim open "myapp://home"
   sim open "iPhone 15 Pro" "myapp://home"
-  sim open "Pixel_7_API_34" "https://example.com"`,
+  sim open "Pixel_7_API_34" "https://example.com"
+  sim open --save checkout "myapp://checkout?cart={cart}"
+  sim open @checkout cart=42
+  sim open --list
+
+Android intent options pick the handler when several apps accept the same link:
+  sim open "https://example.com/item/1" --package com.example.app
+  sim open "myapp://home" --component com.example.app/.MainActivity --extra debug:bool=true
+  sim open "myapp://home" --flags "NEW_TASK|CLEAR_TOP" --wait`,
 	ValidArgsFunction: validDeviceAndFileArgs,
-	Args:              cobra.RangeArgs(1, 2),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			return cobra.NoArgs(cmd, args)
+		}
+		if del, _ := cmd.Flags().GetString("delete"); del != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		if save, _ := cmd.Flags().GetString("save"); save != "" {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var deviceID, url string
-
-		if len(args) == 1 {
-			// Only URL provided — auto-select the first booted device.
-			url = args[0]
-			deviceID = ""
-		} else {
-			deviceID = args[0]
-			url = args[1]
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			listDeeplinks()
+			return nil
+		}
+		if del, _ := cmd.Flags().GetString("delete"); del != "" {
+			return DeleteDeeplink(del)
+		}
+		if save, _ := cmd.Flags().GetString("save"); save != "" {
+			return SaveDeeplink(save, args[0])
+		}
+
+		deviceID, target, params, err := splitOpenArgs(args)
+		if err != nil {
+			return err
 		}
 
-		return openURL(deviceID, url)
+		url, err := ExpandDeeplink(target, params)
+		if err != nil {
+			return err
+		}
+
+		return openURL(deviceID, url, intentFromFlags(cmd))
 	},
 }
 
+func init() {
+	openCmd.Flags().String("save", "", "Save the URL as a named deeplink ({param} placeholders allowed)")
+	openCmd.Flags().String("delete", "", "Delete a saved deeplink")
+	openCmd.Flags().Bool("list", false, "List saved deeplinks")
+	openCmd.Flags().String("package", "", "Android: only let this package handle the URL")
+	openCmd.Flags().String("component", "", "Android: start this activity (package/.Activity)")
+	openCmd.Flags().String("action", "", "Android: intent action (default android.intent.action.VIEW)")
+	openCmd.Flags().StringArray("category", nil, "Android: intent category (repeatable)")
+	openCmd.Flags().StringArray("extra", nil, "Android: intent extra as key:type=value (string, int, long, float, double, bool, uri)")
+	openCmd.Flags().String("flags", "", "Android: intent flags as a number or names (NEW_TASK|CLEAR_TOP)")
+	openCmd.Flags().Bool("wait", false, "Android: wait for the launch and report its time (am start -W)")
+	_ = openCmd.RegisterFlagCompletionFunc("delete", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return DeeplinkNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// splitOpenArgs splits open's arguments into device, target and key=value parameters.
+// Parameters are only accepted after a saved @name or a URL containing {param} placeholders.
+func splitOpenArgs(args []string) (deviceID, target string, params []string, err error) {
+	idx := 0
+	if len(args) > 1 && !strings.HasPrefix(args[0], deeplinkPrefix) && !strings.Contains(args[0], ":") {
+		deviceID = args[0]
+		idx = 1
+	}
+	target, params = args[idx], args[idx+1:]
+
+	if len(params) > 0 && !strings.HasPrefix(target, deeplinkPrefix) && len(DeeplinkParams(target)) == 0 {
+		return "", "", nil, fmt.Errorf("too many arguments; expected [device-name-or-udid] <url>") //nolint:err113
+	}
+
+	return deviceID, target, params, nil
+}
+
+// intentFromFlags collects the Android intent options from open's flags.
+func intentFromFlags(cmd *cobra.Command) AndroidIntent {
+	var intent AndroidIntent
+	intent.Package, _ = cmd.Flags().GetString("package")
+	intent.Component, _ = cmd.Flags().GetString("component")
+	intent.Action, _ = cmd.Flags().GetString("action")
+	intent.Categories, _ = cmd.Flags().GetStringArray("category")
+	intent.Extras, _ = cmd.Flags().GetStringArray("extra")
+	intent.Flags, _ = cmd.Flags().GetString("flags")
+	intent.Wait, _ = cmd.Flags().GetBool("wait")
+
+	return intent
+}
+
+// listDeeplinks prints the saved deeplinks and their parameters.
+func listDeeplinks() {
+	links := Deeplinks()
+	if len(links) == 0 {
+		PrintInfo("No saved deeplinks. Save one with: sim open --save <name> <url>")
+		return
+	}
+
+	rows := make([][]string, 0, len(links))
+	for _, name := range DeeplinkNames() {
+		rows = append(rows, []string{deeplinkPrefix + name, links[name], strings.Join(DeeplinkParams(links[name]), ", ")})
+	}
+
+	RenderTable([]string{"Name", "URL", "Parameters"}, rows)
+}
+
 // openURL dispatches to the appropriate platform handler.
 // Pass an empty deviceID to auto-select the first booted device.
-func openURL(deviceID, url string) error {
+// The intent options only apply to Android and are ignored on iOS.
+func openURL(deviceID, url string, intent AndroidIntent) error {
 	if runtime.GOOS == DarwinOS {
 		if found, err := openIOSUrl(deviceID, url); found {
+			if err == nil && !intent.IsZero() {
+				PrintInfo("Android intent options were ignored on the iOS simulator.")
+			}
+
 			return err
 		}
 	}
 
-	if found, err := openAndroidUrl(deviceID, url); found {
+	if found, err := openAndroidUrl(deviceID, url, intent); found {
 		return err
 	}
 
@@ -100,19 +202,36 @@ func openIOSUrl(deviceID, url string) (bool, error) {
 // openAndroidUrl opens a URL on a running Android emulator.
 // If deviceID is empty, uses any running emulator.
 // Returns (true, nil) on success, (true, err) if found but failed, (false, nil) if not found.
-func openAndroidUrl(deviceID, url string) (bool, error) {
+func openAndroidUrl(deviceID, url string, intent AndroidIntent) (bool, error) {
 	udid, name := FindRunningAndroidEmulator(deviceID)
 	if udid == "" {
 		return false, nil
 	}
+
+	startArgs, err := intent.StartArgs(url)
+	if err != nil {
+		return true, err
+	}
+
 	PrintInfo(fmt.Sprintf("Opening URL on Android emulator '%s'...", name))
-	cmdArgs := []string{"-s", udid, "shell", "am", "start", "-a", "android.intent.action.VIEW", "-d", url}
+	cmdArgs := append([]string{"-s", udid, "shell"}, startArgs...)
 
-	if output, err := packageExecutor.Output(CmdAdb, cmdArgs...); err != nil {
+	output, err := packageExecutor.Output(CmdAdb, cmdArgs...)
+	if err != nil {
 		return true, fmt.Errorf("failed to open URL on Android emulator: %w\nOutput: %s", err, string(output))
 	}
 
-	PrintInfo("URL opened successfully.")
+	// am start exits 0 even when no activity handles the intent, reporting it as "Error: ...".
+	timing, err := parseAmStartOutput(string(output))
+	if err != nil {
+		return true, fmt.Errorf("failed to open URL on Android emulator: %w", err)
+	}
+
+	if timing != "" {
+		PrintInfo(fmt.Sprintf("URL opened successfully in %s.", timing))
+	} else {
+		PrintInfo("URL opened successfully.")
+	}
 
 	return true, nil
 }
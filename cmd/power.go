@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var powerCmd = &cobra.Command{
+	Use:   "power",
+	Short: "Simulate battery level, charging and Doze",
+	Long: `Change the simulated battery and power state of a running device.
+
+Android emulators use 'adb emu power' for the battery and 'dumpsys deviceidle' for Doze.
+iOS simulators have no battery, so the level is shown through a status bar override;
+Doze is Android-only.`,
+}
+
+var powerBatteryCmd = &cobra.Command{
+	Use:               "battery [device-name-or-udid] <level>",
+	Short:             "Set the battery level (0-100) and charging state",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, value := splitDeviceArg(args)
+
+		level, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil {
+			return fmt.Errorf("%w: battery level must be a number, got %q", ErrInvalidSetting, value)
+		}
+		charging, _ := cmd.Flags().GetBool("charging")
+
+		return SetBattery(deviceID, level, charging)
+	},
+}
+
+var powerDozeCmd = &cobra.Command{
+	Use:               "doze [device-name-or-udid] <enter|exit>",
+	Short:             "Force the device into or out of Doze (Android only)",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, action := splitDeviceArg(args)
+
+		return SetDoze(deviceID, action)
+	},
+}
+
+var powerResetCmd = &cobra.Command{
+	Use:               "reset [device-name-or-udid]",
+	Short:             "Restore the real battery and leave Doze",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		return ResetPower(deviceID)
+	},
+}
+
+func init() {
+	powerBatteryCmd.Flags().Bool("charging", false, "Show the battery as charging")
+
+	powerCmd.AddCommand(powerBatteryCmd)
+	powerCmd.AddCommand(powerDozeCmd)
+	powerCmd.AddCommand(powerResetCmd)
+}
+
+// SetBattery sets the simulated battery level and charging state.
+// Pass an empty deviceID to use the first running device.
+func SetBattery(deviceID string, level int, charging bool) error {
+	if level < 0 || level > 100 {
+		return fmt.Errorf("%w: battery level must be 0-100, got %d", ErrInvalidSetting, level)
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	state := "discharging"
+	if charging {
+		state = "charging"
+	}
+
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+
+		args := append([]string{CmdSimctl, "status_bar", udid, "override"}, iosBatteryArgs(level, charging)...)
+		if err := packageExecutor.Run(CmdXCrun, args...); err != nil {
+			return fmt.Errorf("failed to override iOS battery: %w", err)
+		}
+
+		PrintSuccess(fmt.Sprintf("Status bar battery of '%s' set to %d%% (%s).", name, level, state))
+
+		return nil
+	}
+
+	ac := "off"
+	if charging {
+		ac = "on"
+	}
+
+	for _, args := range [][]string{
+		{"ac", ac},
+		{"status", state},
+		{"capacity", strconv.Itoa(level)},
+	} {
+		if err := runAndroidPower(udid, args...); err != nil {
+			return err
+		}
+	}
+
+	PrintSuccess(fmt.Sprintf("Battery of '%s' set to %d%% (%s).", name, level, state))
+
+	return nil
+}
+
+// SetDoze forces an Android emulator into ("enter") or out of ("exit") Doze.
+// Pass an empty deviceID to use the first running device.
+func SetDoze(deviceID, action string) error {
+	action = strings.ToLower(action)
+
+	var steps [][]string
+	switch action {
+	case "enter":
+		steps = [][]string{{"battery", "unplug"}, {"deviceidle", "force-idle"}}
+	case "exit":
+		steps = [][]string{{"deviceidle", "unforce"}, {"battery", "reset"}}
+	default:
+		return fmt.Errorf("%w: doze action must be enter or exit, got %q", ErrInvalidSetting, action)
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+	if !isAndroid {
+		return fmt.Errorf("%w: Doze is an Android power mode", ErrUnsupportedPlatform)
+	}
+
+	for _, step := range steps {
+		if err := runAndroidDumpsys(udid, step...); err != nil {
+			return err
+		}
+	}
+
+	if action == "enter" {
+		PrintSuccess(fmt.Sprintf("'%s' forced into Doze. Run 'sim power doze exit' to leave it.", name))
+	} else {
+		PrintSuccess(fmt.Sprintf("'%s' left Doze.", name))
+	}
+
+	return nil
+}
+
+// ResetPower leaves Doze and restores the default battery state. On iOS only the battery
+// status bar override is removed; other status bar overrides are kept.
+// Pass an empty deviceID to use the first running device.
+func ResetPower(deviceID string) error {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+
+		// simctl can only clear every override, so the others are read first and put back.
+		overrides, err := iosStatusBarOverrides(udid)
+		if err != nil {
+			return err
+		}
+		if err := setIOSStatusBarOverrides(udid, withoutIOSBattery(overrides)); err != nil {
+			return fmt.Errorf("failed to reset iOS battery: %w", err)
+		}
+
+		PrintSuccess(fmt.Sprintf("Status bar battery of '%s' restored.", name))
+
+		return nil
+	}
+
+	if err := runAndroidDumpsys(udid, "deviceidle", "unforce"); err != nil {
+		return err
+	}
+	if err := runAndroidDumpsys(udid, "battery", "reset"); err != nil {
+		return err
+	}
+	// The emulator boots on AC power with a full, charging battery.
+	for _, args := range [][]string{
+		{"ac", "on"},
+		{"status", "charging"},
+		{"capacity", "100"},
+	} {
+		if err := runAndroidPower(udid, args...); err != nil {
+			return err
+		}
+	}
+
+	PrintSuccess(fmt.Sprintf("Power state of '%s' restored.", name))
+
+	return nil
+}
+
+func runAndroidPower(udid string, args ...string) error {
+	if err := packageExecutor.Run(CmdAdb, append([]string{"-s", udid, "emu", "power"}, args...)...); err != nil {
+		return fmt.Errorf("failed to set emulator power %s: %w", args[0], err)
+	}
+
+	return nil
+}
+
+func runAndroidDumpsys(udid string, args ...string) error {
+	if err := packageExecutor.Run(CmdAdb, append([]string{"-s", udid, "shell", "dumpsys"}, args...)...); err != nil {
+		return fmt.Errorf("failed to run dumpsys %s: %w", strings.Join(args, " "), err)
+	}
+
+	return nil
+}
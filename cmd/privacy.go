@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	privacyGrant  = "grant"
+	privacyRevoke = "revoke"
+	privacyReset  = "reset"
+	privacyAll    = "all"
+)
+
+// privacyService maps a platform-neutral service name to its iOS and Android equivalents.
+type privacyService struct {
+	ios     string   // simctl privacy service, empty if unsupported on iOS
+	android []string // runtime permissions, empty if unsupported on Android
+	appOp   string   // Android app-op toggled alongside the permissions
+}
+
+// privacyServices is the single set of service names accepted by 'sim privacy'.
+var privacyServices = map[string]privacyService{
+	"calendar": {
+		ios:     "calendar",
+		android: []string{"android.permission.READ_CALENDAR", "android.permission.WRITE_CALENDAR"},
+	},
+	"camera": {
+		android: []string{"android.permission.CAMERA"},
+	},
+	"contacts": {
+		ios:     "contacts",
+		android: []string{"android.permission.READ_CONTACTS", "android.permission.WRITE_CONTACTS"},
+	},
+	"location": {
+		ios:     "location",
+		android: []string{"android.permission.ACCESS_FINE_LOCATION", "android.permission.ACCESS_COARSE_LOCATION"},
+	},
+	"location-always": {
+		ios: "location-always",
+		android: []string{
+			"android.permission.ACCESS_FINE_LOCATION",
+			"android.permission.ACCESS_COARSE_LOCATION",
+			"android.permission.ACCESS_BACKGROUND_LOCATION",
+		},
+	},
+	"media-library": {
+		ios:     "media-library",
+		android: []string{"android.permission.READ_MEDIA_AUDIO"},
+	},
+	"microphone": {
+		ios:     "microphone",
+		android: []string{"android.permission.RECORD_AUDIO"},
+	},
+	"motion": {
+		ios:     "motion",
+		android: []string{"android.permission.ACTIVITY_RECOGNITION"},
+	},
+	"notifications": {
+		android: []string{"android.permission.POST_NOTIFICATIONS"},
+		appOp:   "POST_NOTIFICATION",
+	},
+	"photos": {
+		ios: "photos",
+		android: []string{
+			"android.permission.READ_MEDIA_IMAGES",
+			"android.permission.READ_MEDIA_VIDEO",
+			"android.permission.READ_EXTERNAL_STORAGE",
+		},
+	},
+	"photos-add": {
+		ios: "photos-add",
+	},
+	"reminders": {
+		ios: "reminders",
+	},
+	"siri": {
+		ios: "siri",
+	},
+}
+
+// PrivacyProfile is a set of permission changes for one app that can be applied in one go.
+type PrivacyProfile struct {
+	App         string            `json:"app"`
+	Permissions map[string]string `json:"permissions"`
+}
+
+var privacyCmd = &cobra.Command{
+	Use:   "privacy",
+	Short: "Grant, revoke or reset app permissions",
+	Long: `Manage privacy permissions of an installed app so UI tests don't stop at permission dialogs.
+
+Service names are shared between platforms and map to 'simctl privacy' on iOS
+and 'pm grant'/'pm revoke'/'appops' on Android.
+
+Services: ` + strings.Join(PrivacyServiceNames(), ", ") + `
+
+Multiple services may be given as a comma-separated list.`,
+}
+
+var privacyGrantCmd = newPrivacyActionCmd(privacyGrant, "Grant a permission to an app")
+
+var privacyRevokeCmd = newPrivacyActionCmd(privacyRevoke, "Revoke a permission from an app")
+
+var privacyResetCmd = newPrivacyActionCmd(privacyReset, "Reset a permission so the app asks again")
+
+var privacyApplyCmd = &cobra.Command{
+	Use:   "apply [device-name-or-udid] <profile.json>",
+	Short: "Apply a permission profile file",
+	Long: `Apply every permission change listed in a profile file.
+
+Example profile:
+  {
+    "app": "com.example.app",
+    "permissions": {
+      "camera": "grant",
+      "location": "revoke",
+      "notifications": "reset"
+    }
+  }`,
+	ValidArgsFunction: validDeviceAndFileArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID, profilePath string
+		if len(args) == 1 {
+			profilePath = args[0]
+		} else {
+			deviceID = args[0]
+			profilePath = args[1]
+		}
+
+		return ApplyPrivacyProfile(deviceID, profilePath)
+	},
+}
+
+func newPrivacyActionCmd(action, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:               action + " [device-name-or-udid] <bundle-id-or-package> <service>",
+		Short:             short,
+		ValidArgsFunction: validDeviceArgs,
+		Args:              cobra.RangeArgs(1, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, _ := cmd.Flags().GetBool(privacyAll)
+
+			var deviceID, appID, service string
+			switch {
+			case all && len(args) == 1:
+				appID = args[0]
+			case all && len(args) == 2:
+				deviceID = args[0]
+				appID = args[1]
+			case !all && len(args) == 2:
+				appID = args[0]
+				service = args[1]
+			case !all && len(args) == 3:
+				deviceID = args[0]
+				appID = args[1]
+				service = args[2]
+			case all:
+				return fmt.Errorf("expected [device] <app> with --all") //nolint:err113
+			default:
+				return fmt.Errorf("expected [device] <app> <service>") //nolint:err113
+			}
+
+			services := []string{privacyAll}
+			if !all {
+				services = strings.Split(service, ",")
+			}
+
+			return SetAppPrivacy(deviceID, action, appID, services)
+		},
+	}
+}
+
+func init() {
+	for _, c := range []*cobra.Command{privacyGrantCmd, privacyRevokeCmd, privacyResetCmd} {
+		c.Flags().Bool(privacyAll, false, "Apply to every supported service")
+		privacyCmd.AddCommand(c)
+	}
+	privacyCmd.AddCommand(privacyApplyCmd)
+}
+
+// PrivacyServiceNames returns the sorted list of supported privacy service names.
+func PrivacyServiceNames() []string {
+	names := make([]string, 0, len(privacyServices))
+	for name := range privacyServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SetAppPrivacy grants, revokes or resets the given services for appID.
+// Pass "all" as the only service to change every supported service.
+// Pass an empty deviceID to use the first running device.
+func SetAppPrivacy(deviceID, action, appID string, services []string) error {
+	if err := validatePrivacyAction(action); err != nil {
+		return err
+	}
+	if err := validateAppID(appID); err != nil {
+		return err
+	}
+	for _, s := range services {
+		if _, ok := privacyServices[s]; !ok && s != privacyAll {
+			return fmt.Errorf("%w: %q (supported: %s)", ErrUnknownPrivacyService, s, strings.Join(PrivacyServiceNames(), ", "))
+		}
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+	if !isAndroid && runtime.GOOS != DarwinOS {
+		return ErrIOSMacOnly
+	}
+
+	label := strings.Join(services, ", ")
+	err = RunSpinner(fmt.Sprintf("Applying %s %s for %s on '%s'...", action, label, appID, name), func() error {
+		for _, s := range services {
+			if applyErr := applyPrivacy(udid, isAndroid, action, appID, s); applyErr != nil {
+				return applyErr
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Permission %s applied to %s for %s.", action, label, appID))
+
+	return nil
+}
+
+// ApplyPrivacyProfile applies every permission change in the profile file at profilePath.
+func ApplyPrivacyProfile(deviceID, profilePath string) error {
+	profile, err := LoadPrivacyProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	// Apply in a stable order so runs are reproducible.
+	services := make([]string, 0, len(profile.Permissions))
+	for s := range profile.Permissions {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+
+	for _, s := range services {
+		if err := SetAppPrivacy(deviceID, profile.Permissions[s], profile.App, []string{s}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadPrivacyProfile reads and validates a permission profile file.
+func LoadPrivacyProfile(profilePath string) (*PrivacyProfile, error) {
+	data, err := os.ReadFile(profilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read permission profile: %w", err)
+	}
+
+	var profile PrivacyProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("invalid permission profile %s: %w", profilePath, err)
+	}
+
+	if profile.App == "" {
+		return nil, fmt.Errorf("permission profile %s: %w", profilePath, ErrInvalidAppID)
+	}
+	for s, action := range profile.Permissions {
+		if _, ok := privacyServices[s]; !ok && s != privacyAll {
+			return nil, fmt.Errorf("permission profile %s: %w: %q", profilePath, ErrUnknownPrivacyService, s)
+		}
+		if err := validatePrivacyAction(action); err != nil {
+			return nil, fmt.Errorf("permission profile %s: %w", profilePath, err)
+		}
+	}
+
+	return &profile, nil
+}
+
+func validatePrivacyAction(action string) error {
+	switch action {
+	case privacyGrant, privacyRevoke, privacyReset:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q (expected grant, revoke or reset)", ErrInvalidPrivacyAction, action)
+	}
+}
+
+// applyPrivacy applies a single action/service pair on the device.
+func applyPrivacy(udid string, isAndroid bool, action, appID, service string) error {
+	if !isAndroid {
+		iosService := privacyAll
+		if service != privacyAll {
+			iosService = privacyServices[service].ios
+		}
+		if iosService == "" {
+			return fmt.Errorf("%w: %s on iOS", ErrPrivacyUnsupported, service)
+		}
+
+		if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "privacy", udid, action, iosService, appID); err != nil {
+			return fmt.Errorf("failed to %s %s on iOS simulator: %w", action, service, err)
+		}
+
+		return nil
+	}
+
+	if service != privacyAll {
+		svc := privacyServices[service]
+		if len(svc.android) == 0 {
+			return fmt.Errorf("%w: %s on Android", ErrPrivacyUnsupported, service)
+		}
+
+		applied, err := applyAndroidPrivacy(udid, action, appID, svc)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return fmt.Errorf("%s does not request the %s permission", appID, service) //nolint:err113
+		}
+
+		return nil
+	}
+
+	for _, name := range PrivacyServiceNames() {
+		if svc := privacyServices[name]; len(svc.android) > 0 {
+			if _, err := applyAndroidPrivacy(udid, action, appID, svc); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyAndroidPrivacy applies action to each runtime permission of svc. Permissions the
+// app does not request are skipped; applied reports whether any permission was changed.
+func applyAndroidPrivacy(udid, action, appID string, svc privacyService) (applied bool, err error) {
+	for _, perm := range svc.android {
+		var args []string
+		switch action {
+		case privacyGrant:
+			args = []string{"pm", "grant", appID, perm}
+		default:
+			args = []string{"pm", "revoke", appID, perm}
+		}
+
+		if err := packageExecutor.Run(CmdAdb, append([]string{"-s", udid, "shell"}, args...)...); err != nil {
+			if isAndroidPermissionSkippable(err) {
+				continue
+			}
+
+			return applied, fmt.Errorf("failed to %s %s: %w", action, perm, err)
+		}
+		applied = true
+
+		if action == privacyReset {
+			// Clearing the user-set flags makes the app prompt again on next request.
+			if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "pm", "clear-permission-flags",
+				appID, perm, "user-set", "user-fixed"); err != nil {
+				return applied, fmt.Errorf("failed to reset %s: %w", perm, err)
+			}
+		}
+	}
+
+	if svc.appOp != "" {
+		mode := map[string]string{privacyGrant: "allow", privacyRevoke: "ignore", privacyReset: "default"}[action]
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "appops", "set", appID, svc.appOp, mode); err != nil {
+			return applied, fmt.Errorf("failed to set app-op %s: %w", svc.appOp, err)
+		}
+		applied = true
+	}
+
+	return applied, nil
+}
+
+// isAndroidPermissionSkippable reports whether a pm grant/revoke failure only means the
+// permission does not apply to this app or API level.
+func isAndroidPermissionSkippable(err error) bool {
+	msg := err.Error()
+
+	return strings.Contains(msg, "has not requested permission") ||
+		strings.Contains(msg, "Unknown permission") ||
+		strings.Contains(msg, "is not a changeable permission type")
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// androidHostLoopback is the address Android emulators use to reach the host's loopback interface.
+	androidHostLoopback = "10.0.2.2"
+	// androidNoProxy is the http_proxy value that disables the global proxy without a reboot.
+	androidNoProxy = ":0"
+	// defaultNetworkService is the macOS network service configured by --host.
+	defaultNetworkService = "Wi-Fi"
+)
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Route device traffic through an HTTP proxy",
+	Long: `Configure an HTTP proxy such as Charles or mitmproxy on a running device.
+
+Android emulators use the global http_proxy setting; localhost is rewritten to
+10.0.2.2 so the emulator reaches the proxy on the host.
+
+iOS simulators share the Mac's network settings. Pass --host to set the proxy of a
+macOS network service instead (this affects every app on the Mac).`,
+}
+
+var proxySetCmd = &cobra.Command{
+	Use:               "set [device-name-or-udid] <host:port>",
+	Short:             "Set the HTTP proxy",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, addr := splitDeviceArg(args)
+		configureHost, _ := cmd.Flags().GetBool("host")
+		service, _ := cmd.Flags().GetString("service")
+
+		return SetProxy(deviceID, addr, configureHost, service)
+	},
+}
+
+var proxyClearCmd = &cobra.Command{
+	Use:               "clear [device-name-or-udid]",
+	Short:             "Remove the HTTP proxy",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+		configureHost, _ := cmd.Flags().GetBool("host")
+		service, _ := cmd.Flags().GetString("service")
+
+		return ClearProxy(deviceID, configureHost, service)
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{proxySetCmd, proxyClearCmd} {
+		c.Flags().Bool("host", false, "iOS: configure the macOS proxy of a network service")
+		c.Flags().String("service", defaultNetworkService, "macOS network service used with --host")
+	}
+
+	proxyCmd.AddCommand(proxySetCmd)
+	proxyCmd.AddCommand(proxyClearCmd)
+}
+
+// ParseProxyAddress splits and validates a host:port proxy address.
+func ParseProxyAddress(addr string) (host, port string, err error) {
+	host, port, err = net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return "", "", fmt.Errorf("%w: expected host:port, got %q", ErrInvalidProxy, addr)
+	}
+
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return "", "", fmt.Errorf("%w: invalid port %q", ErrInvalidProxy, port)
+	}
+
+	return host, port, nil
+}
+
+// AndroidProxyHost rewrites loopback hosts to the address that reaches the host from an emulator.
+func AndroidProxyHost(host string) string {
+	if host == "localhost" {
+		return androidHostLoopback
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return androidHostLoopback
+	}
+
+	return host
+}
+
+// SetProxy routes the device's HTTP traffic through addr. On iOS the Mac's proxy for
+// service is changed only when configureHost is set; otherwise guidance is printed.
+func SetProxy(deviceID, addr string, configureHost bool, service string) error {
+	host, port, err := ParseProxyAddress(addr)
+	if err != nil {
+		return err
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if isAndroid {
+		proxy := net.JoinHostPort(AndroidProxyHost(host), port)
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "settings", "put", "global", "http_proxy", proxy); err != nil {
+			return fmt.Errorf("failed to set Android proxy: %w", err)
+		}
+
+		PrintSuccess(fmt.Sprintf("Proxy of '%s' set to %s.", name, proxy))
+
+		return nil
+	}
+
+	if runtime.GOOS != DarwinOS {
+		return ErrIOSMacOnly
+	}
+
+	if !configureHost {
+		PrintInfo(fmt.Sprintf("iOS simulators use the Mac's network settings, so '%s' cannot have its own proxy.", name))
+		PrintInfo(fmt.Sprintf("Set %s as the HTTP and HTTPS proxy in System Settings > Network, or run:", addr))
+		PrintInfo(fmt.Sprintf("  sim proxy set --host --service %q %s", service, addr))
+
+		return nil
+	}
+
+	for _, kind := range []string{"-setwebproxy", "-setsecurewebproxy"} {
+		if err := packageExecutor.Run(CmdNetworkSetup, kind, service, host, port); err != nil {
+			return fmt.Errorf("failed to set macOS proxy for %q: %w", service, err)
+		}
+	}
+
+	PrintSuccess(fmt.Sprintf("macOS proxy for %q set to %s; all iOS simulators now use it.", service, addr))
+
+	return nil
+}
+
+// ClearProxy removes the device's HTTP proxy. On iOS the Mac's proxy for service is
+// disabled only when configureHost is set.
+func ClearProxy(deviceID string, configureHost bool, service string) error {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if isAndroid {
+		if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "settings", "put", "global", "http_proxy", androidNoProxy); err != nil {
+			return fmt.Errorf("failed to clear Android proxy: %w", err)
+		}
+
+		PrintSuccess(fmt.Sprintf("Proxy removed from '%s'.", name))
+
+		return nil
+	}
+
+	if runtime.GOOS != DarwinOS {
+		return ErrIOSMacOnly
+	}
+
+	if !configureHost {
+		PrintInfo("iOS simulators use the Mac's network settings. Turn the proxy off in System Settings > Network, or run:")
+		PrintInfo(fmt.Sprintf("  sim proxy clear --host --service %q", service))
+
+		return nil
+	}
+
+	for _, kind := range []string{"-setwebproxystate", "-setsecurewebproxystate"} {
+		if err := packageExecutor.Run(CmdNetworkSetup, kind, service, "off"); err != nil {
+			return fmt.Errorf("failed to clear macOS proxy for %q: %w", service, err)
+		}
+	}
+
+	PrintSuccess(fmt.Sprintf("macOS proxy for %q turned off.", service))
+
+	return nil
+}
+
+// GetAndroidProxy returns the global HTTP proxy of an Android emulator, or "" if none is set.
+func GetAndroidProxy(udid string) string {
+	out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "settings", "get", "global", "http_proxy")
+	if err != nil {
+		return ""
+	}
+
+	proxy := strings.TrimSpace(string(out))
+	if proxy == "null" || proxy == androidNoProxy {
+		return ""
+	}
+
+	return proxy
+}
+
+// GetHostProxy returns the Mac's active HTTP proxy from 'scutil --proxy', or "" if none is enabled.
+// iOS simulators use this proxy.
+func GetHostProxy() string {
+	out, err := packageExecutor.Output(CmdScutil, "--proxy")
+	if err != nil {
+		return ""
+	}
+
+	values := map[string]string{}
+	for line := range strings.SplitSeq(string(out), "\n") {
+		if key, value, ok := strings.Cut(line, " : "); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	if values["HTTPEnable"] != "1" || values["HTTPProxy"] == "" {
+		return ""
+	}
+
+	return net.JoinHostPort(values["HTTPProxy"], values["HTTPPort"])
+}
@@ -1,113 +1,349 @@
 package cmd
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
-var pushTemplate bool
+var (
+	pushTemplate   string
+	pushAndroidVia string
+	pushVars       []string
+	pushRepeat     int
+	pushInterval   time.Duration
+)
+
+// PushOptions controls how a push payload is rendered and sent.
+type PushOptions struct {
+	Vars       map[string]string // Values for {{name}} placeholders or dotted payload paths.
+	Repeat     int               // Number of times to send the payload; values below 1 send once.
+	Interval   time.Duration     // Delay between repeated sends.
+	AndroidVia string            // Android delivery method, see AndroidPushAuto.
+}
 
 var pushCmd = &cobra.Command{
-	Use:   "push [device-name-or-udid] <bundle-id> <payload.json>",
-	Short: "Send a push notification (iOS only)",
-	Long: `Send a simulated push notification to an app on an iOS simulator.
-	
-Provide a valid APNs payload JSON file. Android emulators do not support this command.`,
+	Use:   "push [device-name-or-udid] [bundle-id] <payload.json|dir>",
+	Short: "Send a push notification",
+	Long: `Send a simulated push notification to an app.
+
+Provide a valid APNs payload JSON file. iOS simulators receive it through 'simctl push'.
+Android emulators receive a translation of it: alerts are posted with 'cmd notification
+post' and silent (content-available) pushes are broadcast to the app's FCM receiver
+with the custom keys as string extras. Use --android-via to pick the method.
+
+The bundle ID may be omitted when the payload has a "Simulator Target Bundle" key or a
+default app is configured ('sim config set defaultApp <id>'). With two arguments, the
+first is the device when it names a running device and the bundle ID otherwise. The
+device defaults to the configured defaultDevice when it is running, then the first
+running device. Given a directory, every .apns and .json file in it is sent in name
+order.
+
+Payloads may contain {{name}} or {{name|default}} placeholders, filled with --set name=value.
+--set also accepts dotted paths such as aps.badge=3. Every payload is checked against the
+APNs rules (known aps keys and types, 4 KB limit) before it is sent.
+
+Templates: ` + strings.Join(PushTemplateNames(), ", ") + ` (e.g. sim push --template silent).`,
 	ValidArgsFunction: validDeviceArgs,
 	Args:              cobra.RangeArgs(0, 3),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if pushTemplate {
-			return GeneratePushTemplate()
+		if cmd.Flags().Changed("template") {
+			// "sim push -t silent" parses silent as an argument because the flag value is optional.
+			name := pushTemplate
+			if len(args) == 1 && name == defaultPushTemplate {
+				name = args[0]
+			}
+
+			return GeneratePushTemplate(name)
 		}
 
-		if len(args) < 2 {
-			return fmt.Errorf("bundle-id and payload.json are required unless using --template") //nolint:err113
+		if len(args) == 0 {
+			return fmt.Errorf("a payload file or directory is required unless using --template") //nolint:err113
 		}
 
-		var deviceID, bundleID, payloadPath string
-		if len(args) == 2 {
-			bundleID = args[0]
-			payloadPath = args[1]
-		} else {
-			deviceID = args[0]
-			bundleID = args[1]
-			payloadPath = args[2]
+		deviceID, bundleID, payloadPath, err := splitPushArgs(args)
+		if err != nil {
+			return err
 		}
 
-		return SendPushNotification(deviceID, bundleID, payloadPath)
+		vars, err := ParsePushVars(pushVars)
+		if err != nil {
+			return err
+		}
+
+		return SendPush(deviceID, bundleID, payloadPath, PushOptions{
+			Vars:       vars,
+			Repeat:     pushRepeat,
+			Interval:   pushInterval,
+			AndroidVia: pushAndroidVia,
+		})
 	},
 }
 
 func init() {
-	pushCmd.Flags().BoolVarP(&pushTemplate, "template", "t", false, "Generate a sample push payload template (push.json)")
+	pushCmd.Flags().StringVarP(&pushTemplate, "template", "t", defaultPushTemplate,
+		"Generate a payload template (push.json): "+strings.Join(PushTemplateNames(), ", "))
+	pushCmd.Flags().Lookup("template").NoOptDefVal = defaultPushTemplate
+	pushCmd.Flags().StringVar(&pushAndroidVia, "android-via", AndroidPushAuto,
+		"Android delivery: auto, notification (cmd notification post) or broadcast (FCM receiver)")
+	pushCmd.Flags().StringArrayVar(&pushVars, "set", nil, "Set a placeholder or payload path (key=value, repeatable)")
+	pushCmd.Flags().IntVar(&pushRepeat, "repeat", 1, "Send the payload N times")
+	pushCmd.Flags().DurationVar(&pushInterval, "interval", 0, "Delay between repeated sends (e.g. 5s)")
+}
+
+// splitPushArgs splits 'push' arguments into device, bundle ID and payload path. With two
+// arguments the first is the device when it matches a running device, else the bundle ID.
+func splitPushArgs(args []string) (deviceID, bundleID, payloadPath string, err error) {
+	payloadPath = args[len(args)-1]
+	switch len(args) {
+	case 2:
+		_, _, _, findErr := FindRunningDevice(args[0])
+		switch {
+		case findErr == nil:
+			deviceID = args[0]
+		case errors.Is(findErr, ErrDeviceNotRunning) || errors.Is(findErr, ErrNoActiveDevice):
+			bundleID = args[0]
+		default:
+			return "", "", "", findErr
+		}
+	case 3:
+		deviceID, bundleID = args[0], args[1]
+	}
+
+	return deviceID, bundleID, payloadPath, nil
 }
 
-func GeneratePushTemplate() error {
-	template := `{
-  "aps": {
-    "alert": {
-      "title": "Test Notification",
-      "body": "This is a simulated push notification from sim-cli"
-    },
-    "sound": "default",
-    "badge": 1
-  },
-  "customKey": "customValue"
-}`
-	err := os.WriteFile("push.json", []byte(template), 0o644)
+// GeneratePushTemplate writes the named payload template to push.json.
+func GeneratePushTemplate(name string) error {
+	template, err := PushTemplate(name)
 	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile("push.json", []byte(template+"\n"), 0o644); err != nil {
 		return fmt.Errorf("failed to write template: %w", err)
 	}
 
-	PrintSuccess("Generated sample push payload at push.json")
+	PrintSuccess(fmt.Sprintf("Generated %s push payload at push.json", strings.ToLower(name)))
 
 	return nil
 }
 
+// SendPushNotification sends the APNs payload at payloadPath to bundleID (a package name on Android).
 func SendPushNotification(deviceID, bundleID, payloadPath string) error {
-	if runtime.GOOS != DarwinOS {
-		return ErrIOSMacOnly
+	return SendPush(deviceID, bundleID, payloadPath, PushOptions{AndroidVia: AndroidPushAuto})
+}
+
+// pushJob is a rendered payload ready to send.
+type pushJob struct {
+	path     string // Payload file as given.
+	bundleID string
+	content  []byte // File contents before rendering.
+	payload  []byte // Rendered, validated payload.
+}
+
+// findPushDevice resolves deviceID, or else the configured default device when it is
+// running, or else the first running device.
+func findPushDevice(deviceID, defaultDevice string) (string, string, bool, error) {
+	if deviceID == "" && defaultDevice != "" {
+		udid, name, isAndroid, err := FindRunningDevice(defaultDevice)
+		if !errors.Is(err, ErrDeviceNotRunning) {
+			return udid, name, isAndroid, err
+		}
 	}
 
-	// Validate JSON
-	content, err := os.ReadFile(payloadPath)
+	return FindRunningDevice(deviceID)
+}
+
+// SendPush renders and validates the payload at payloadPath, then sends it to bundleID
+// opts.Repeat times. payloadPath may be a directory, whose .apns and .json files are sent
+// in name order. An empty bundleID is taken from each payload's "Simulator Target Bundle"
+// or the configured defaultApp; an empty deviceID uses the configured defaultDevice or the
+// first running device.
+func SendPush(deviceID, bundleID, payloadPath string, opts PushOptions) error {
+	files, err := pushPayloadFiles(payloadPath)
 	if err != nil {
-		return fmt.Errorf("failed to read payload file: %w", err)
+		return err
 	}
-	if !json.Valid(content) {
-		return fmt.Errorf("invalid JSON payload in %s", payloadPath) //nolint:err113
+
+	config, _ := LoadConfig()
+
+	jobs := make([]pushJob, 0, len(files))
+	for _, file := range files {
+		job, err := preparePush(file, bundleID, config.DefaultApp, opts.Vars)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job)
 	}
 
-	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	udid, name, isAndroid, err := findPushDevice(deviceID, config.DefaultDevice)
 	if err != nil {
 		return err
 	}
 
+	var send func(job pushJob) (string, error)
 	if isAndroid {
-		return fmt.Errorf("push notifications are not supported for Android emulators") //nolint:err113
+		for _, job := range jobs {
+			if err := validateAppID(job.bundleID); err != nil {
+				return err
+			}
+		}
+
+		send = func(job pushJob) (string, error) {
+			via, err := sendAndroidPush(udid, job.bundleID, job.payload, opts.AndroidVia)
+			if err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("Push notification sent to %s on '%s' (%s).", job.bundleID, name, via), nil
+		}
+	} else {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+
+		// simctl reads the payload from disk, so substituted payloads go through a temporary file.
+		for i, job := range jobs {
+			if bytes.Equal(job.payload, job.content) {
+				continue
+			}
+
+			tmp, err := writeTempPushPayload(job.payload)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = os.Remove(tmp) }()
+			jobs[i].path = tmp
+		}
+
+		send = func(job pushJob) (string, error) {
+			absPath, err := filepath.Abs(job.path)
+			if err != nil {
+				return "", fmt.Errorf("invalid payload path: %w", err)
+			}
+
+			err = RunSpinner(fmt.Sprintf("Sending push notification to %s on '%s'...", job.bundleID, name), func() error {
+				if pushErr := packageExecutor.Run(CmdXCrun, CmdSimctl, "push", udid, job.bundleID, absPath); pushErr != nil {
+					return fmt.Errorf("failed to send push notification: %w", pushErr)
+				}
+
+				return nil
+			})
+
+			return "Push notification sent successfully.", err
+		}
+	}
+
+	repeat := max(opts.Repeat, 1)
+	total := repeat * len(jobs)
+	sent := 0
+	for i, job := range jobs {
+		file := filepath.Base(files[i])
+		for range repeat {
+			if sent > 0 && opts.Interval > 0 {
+				time.Sleep(opts.Interval)
+			}
+
+			msg, err := send(job)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			sent++
+
+			if len(jobs) > 1 {
+				msg = file + ": " + msg
+			}
+			if total > 1 {
+				msg = fmt.Sprintf("[%d/%d] %s", sent, total, msg)
+			}
+			PrintSuccess(msg)
+		}
+	}
+
+	return nil
+}
+
+// pushPayloadFiles returns path, or the .apns and .json files in it when it is a directory.
+func pushPayloadFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload file: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
 	}
 
-	absPath, err := filepath.Abs(payloadPath)
+	entries, err := os.ReadDir(path)
 	if err != nil {
-		return fmt.Errorf("invalid payload path: %w", err)
+		return nil, fmt.Errorf("failed to read payload directory: %w", err)
 	}
 
-	err = RunSpinner(fmt.Sprintf("Sending push notification to %s on '%s'...", bundleID, name), func() error {
-		if pushErr := packageExecutor.Run(CmdXCrun, CmdSimctl, "push", udid, bundleID, absPath); pushErr != nil {
-			return fmt.Errorf("failed to send push notification: %w", pushErr)
+	var files []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !entry.IsDir() && (ext == ".apns" || ext == ".json") {
+			files = append(files, filepath.Join(path, entry.Name()))
 		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no .apns or .json files in %s", ErrInvalidPushPayload, path)
+	}
 
-		return nil
-	})
+	return files, nil
+}
+
+// preparePush reads, renders and validates a payload file and resolves its target app.
+func preparePush(path, bundleID, defaultApp string, vars map[string]string) (pushJob, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return pushJob{}, fmt.Errorf("failed to read payload file: %w", err)
+	}
+
+	payload, err := RenderPushPayload(content, vars)
+	if err != nil {
+		return pushJob{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := ValidatePushPayload(payload); err != nil {
+		return pushJob{}, fmt.Errorf("%s: %w", path, err)
+	}
 
-	if err == nil {
-		PrintSuccess("Push notification sent successfully.")
+	if bundleID == "" {
+		bundleID = PushTargetBundle(payload)
+	}
+	if bundleID == "" {
+		bundleID = defaultApp
+	}
+	if bundleID == "" {
+		return pushJob{}, fmt.Errorf("%w: pass a bundle ID, add %q to %s or run 'sim config set defaultApp <id>'",
+			ErrNoPushTarget, simulatorTargetBundleKey, path)
+	}
+
+	return pushJob{path: path, bundleID: bundleID, content: content, payload: payload}, nil
+}
+
+// writeTempPushPayload writes payload to a temporary file and returns its path.
+func writeTempPushPayload(payload []byte) (string, error) {
+	tmp, err := os.CreateTemp("", "sim-push-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary payload: %w", err)
+	}
+
+	_, err = tmp.Write(payload)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write temporary payload: %w", err)
 	}
 
-	return err
+	return tmp.Name(), nil
 }
@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxPushPayloadSize is the APNs limit for regular remote notifications, in bytes.
+	maxPushPayloadSize = 4096
+	// defaultPushTemplate is written by a bare --template.
+	defaultPushTemplate = "alert"
+	// simulatorTargetBundleKey is the top-level payload key simctl reads the target app from.
+	simulatorTargetBundleKey = "Simulator Target Bundle"
+)
+
+// pushVarPattern matches {{name}} and {{name|default}} placeholders.
+var pushVarPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*(?:\|([^}]*))?\}\}`)
+
+// pushTemplates are the payloads written by --template. Placeholders only appear inside
+// strings so every template is valid JSON before substitution.
+var pushTemplates = map[string]string{
+	"alert": `{
+  "aps": {
+    "alert": {
+      "title": "{{title|Test Notification}}",
+      "body": "{{body|This is a simulated push notification from sim-cli}}"
+    },
+    "sound": "default",
+    "badge": 1
+  },
+  "customKey": "customValue"
+}`,
+	"silent": `{
+  "aps": {
+    "content-available": 1
+  },
+  "sync": "{{sync|inbox}}"
+}`,
+	"badge": `{
+  "aps": {
+    "badge": 5
+  }
+}`,
+	"rich-media": `{
+  "aps": {
+    "alert": {
+      "title": "{{title|New photo}}",
+      "body": "{{body|Tap and hold to see the attachment}}"
+    },
+    "mutable-content": 1,
+    "category": "{{category|MEDIA}}"
+  },
+  "media-url": "{{url|https://picsum.photos/600/400}}"
+}`,
+	"mutable-content": `{
+  "aps": {
+    "alert": {
+      "title": "{{title|Encrypted message}}",
+      "body": "{{body|(Decrypting...)}}"
+    },
+    "mutable-content": 1
+  },
+  "ciphertext": "{{ciphertext|c2ltLWNsaQ==}}"
+}`,
+}
+
+// apsValidators describe what is wrong with the value of each known aps key, or return "".
+// Unknown keys are rejected.
+var apsValidators = map[string]func(any) string{
+	"alert":              validateAlert,
+	"badge":              validateNonNegativeInt,
+	"sound":              validateSound,
+	"thread-id":          validateString,
+	"category":           validateString,
+	"content-available":  validateFlag,
+	"mutable-content":    validateFlag,
+	"target-content-id":  validateString,
+	"interruption-level": validateInterruptionLevel,
+	"relevance-score":    validateRelevanceScore,
+	"filter-criteria":    validateString,
+	"stale-date":         validateNonNegativeInt,
+	"content-state":      validateObject,
+	"timestamp":          validateNonNegativeInt,
+	"event":              validateString,
+	"dismissal-date":     validateNonNegativeInt,
+	"attributes-type":    validateString,
+	"attributes":         validateObject,
+}
+
+// alertKeys are the keys allowed in an aps.alert dictionary.
+var alertKeys = map[string]bool{
+	"title": true, "subtitle": true, "body": true, "launch-image": true,
+	"title-loc-key": true, "title-loc-args": true, "subtitle-loc-key": true, "subtitle-loc-args": true,
+	"loc-key": true, "loc-args": true,
+}
+
+// PushTemplateNames returns the names of the built-in payload templates.
+func PushTemplateNames() []string {
+	names := make([]string, 0, len(pushTemplates))
+	for name := range pushTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// PushTemplate returns the payload template with the given name.
+func PushTemplate(name string) (string, error) {
+	t, ok := pushTemplates[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown template %q (available: %s)", ErrInvalidPushPayload, name,
+			strings.Join(PushTemplateNames(), ", "))
+	}
+
+	return t, nil
+}
+
+// PushTargetBundle returns the payload's "Simulator Target Bundle", or "" when it has none.
+func PushTargetBundle(content []byte) string {
+	var payload map[string]any
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return ""
+	}
+	bundle, _ := payload[simulatorTargetBundleKey].(string)
+
+	return bundle
+}
+
+// ParsePushVars parses key=value pairs from --set.
+func ParsePushVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: --set expects key=value, got %q", ErrInvalidPushPayload, pair)
+		}
+		vars[key] = value
+	}
+
+	return vars, nil
+}
+
+// RenderPushPayload fills {{name}} and {{name|default}} placeholders from vars. Values are
+// escaped for use inside JSON strings. Vars that match no placeholder are treated as
+// dotted paths (e.g. aps.badge=3) and set in the payload, parsing the value as JSON when possible.
+func RenderPushPayload(content []byte, vars map[string]string) ([]byte, error) {
+	used := map[string]bool{}
+	var missing []string
+
+	rendered := pushVarPattern.ReplaceAllFunc(content, func(m []byte) []byte {
+		groups := pushVarPattern.FindSubmatch(m)
+		name := string(groups[1])
+		used[name] = true
+
+		value, ok := vars[name]
+		if !ok {
+			if !bytes.Contains(m, []byte("|")) {
+				missing = append(missing, name)
+				return m
+			}
+			value = string(groups[2])
+		}
+
+		escaped, _ := json.Marshal(value)
+
+		return escaped[1 : len(escaped)-1]
+	})
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: no value for %s (use --set name=value)", ErrInvalidPushPayload,
+			strings.Join(missing, ", "))
+	}
+
+	var paths []string
+	for name := range vars {
+		if !used[name] {
+			paths = append(paths, name)
+		}
+	}
+	if len(paths) == 0 {
+		return rendered, nil
+	}
+	sort.Strings(paths)
+
+	var payload map[string]any
+	dec := json.NewDecoder(bytes.NewReader(rendered))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("%w: invalid JSON payload: %w", ErrInvalidPushPayload, err)
+	}
+
+	for _, path := range paths {
+		if err := setPayloadPath(payload, path, vars[path]); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.MarshalIndent(payload, "", "  ")
+}
+
+// setPayloadPath sets a dotted path in payload, creating intermediate dictionaries.
+func setPayloadPath(payload map[string]any, path, raw string) error {
+	var value any = raw
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	var parsed any
+	if err := dec.Decode(&parsed); err == nil && !dec.More() {
+		value = parsed
+	}
+
+	keys := strings.Split(path, ".")
+	node := payload
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := node[key]
+		if !ok {
+			child = map[string]any{}
+			node[key] = child
+		}
+		m, ok := child.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w: cannot set %s: %s is not a dictionary", ErrInvalidPushPayload, path, key)
+		}
+		node = m
+	}
+	node[keys[len(keys)-1]] = value
+
+	return nil
+}
+
+// ValidatePushPayload checks a payload against the APNs rules: a JSON object with an aps
+// dictionary of known keys and value types, no larger than 4 KB.
+func ValidatePushPayload(content []byte) error {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, content); err != nil {
+		return fmt.Errorf("%w: invalid JSON payload: %w", ErrInvalidPushPayload, err)
+	}
+	if compact.Len() > maxPushPayloadSize {
+		return fmt.Errorf("%w: %d bytes exceeds the %d byte APNs limit", ErrInvalidPushPayload, compact.Len(), maxPushPayloadSize)
+	}
+
+	var payload map[string]any
+	dec := json.NewDecoder(&compact)
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return fmt.Errorf("%w: payload must be a JSON object", ErrInvalidPushPayload)
+	}
+
+	aps, ok := payload["aps"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: missing aps dictionary", ErrInvalidPushPayload)
+	}
+
+	keys := make([]string, 0, len(aps))
+	for key := range aps {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		validate, known := apsValidators[key]
+		if !known {
+			return fmt.Errorf("%w: unknown aps key %q", ErrInvalidPushPayload, key)
+		}
+		if problem := validate(aps[key]); problem != "" {
+			return fmt.Errorf("%w: aps.%s %s", ErrInvalidPushPayload, key, problem)
+		}
+	}
+
+	return nil
+}
+
+func validateString(v any) string {
+	if _, ok := v.(string); !ok {
+		return "must be a string"
+	}
+
+	return ""
+}
+
+func validateObject(v any) string {
+	if _, ok := v.(map[string]any); !ok {
+		return "must be a dictionary"
+	}
+
+	return ""
+}
+
+func validateNonNegativeInt(v any) string {
+	n, ok := v.(json.Number)
+	if !ok {
+		return "must be a number"
+	}
+	if i, err := n.Int64(); err != nil || i < 0 {
+		return "must be a non-negative integer"
+	}
+
+	return ""
+}
+
+func validateFlag(v any) string {
+	if n, ok := v.(json.Number); !ok || (n.String() != "0" && n.String() != "1") {
+		return "must be 0 or 1"
+	}
+
+	return ""
+}
+
+func validateRelevanceScore(v any) string {
+	n, ok := v.(json.Number)
+	if !ok {
+		return "must be a number"
+	}
+	if f, err := n.Float64(); err != nil || f < 0 || f > 1 {
+		return "must be between 0 and 1"
+	}
+
+	return ""
+}
+
+func validateInterruptionLevel(v any) string {
+	switch v {
+	case "passive", "active", "time-sensitive", "critical":
+		return ""
+	}
+
+	return "must be passive, active, time-sensitive or critical"
+}
+
+func validateAlert(v any) string {
+	switch alert := v.(type) {
+	case string:
+		return ""
+	case map[string]any:
+		for key := range alert {
+			if !alertKeys[key] {
+				return fmt.Sprintf("has unknown key %q", key)
+			}
+		}
+
+		return ""
+	}
+
+	return "must be a string or dictionary"
+}
+
+func validateSound(v any) string {
+	switch sound := v.(type) {
+	case string:
+		return ""
+	case map[string]any:
+		if _, ok := sound["name"].(string); !ok {
+			return "dictionary needs a name string"
+		}
+
+		return ""
+	}
+
+	return "must be a string or dictionary"
+}
@@ -172,6 +172,24 @@ func init() {
 	rootCmd.AddCommand(openCmd)
 	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(camCmd)
+	rootCmd.AddCommand(appCmd)
+	rootCmd.AddCommand(privacyCmd)
+	rootCmd.AddCommand(locationCmd)
+	rootCmd.AddCommand(statusBarCmd)
+	rootCmd.AddCommand(uiCmd)
+	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(localeCmd)
+	rootCmd.AddCommand(certCmd)
+	rootCmd.AddCommand(proxyCmd)
+	rootCmd.AddCommand(networkCmd)
+	rootCmd.AddCommand(clipboardCmd)
+	rootCmd.AddCommand(inputCmd)
+	rootCmd.AddCommand(biometricCmd)
+	rootCmd.AddCommand(powerCmd)
+	rootCmd.AddCommand(linksCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(filesCmd)
+	rootCmd.AddCommand(seedCmd)
 
 	// deleteCmd flags
 	deleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
@@ -185,6 +203,10 @@ func init() {
 	// screenshotCmd flags
 	screenshotCmd.Flags().BoolP("copy", "c", false, "Copy the screenshot to the clipboard")
 	screenshotCmd.Flags().String("output-dir", "", "Directory to save the screenshot (default: current directory)")
+	screenshotCmd.Flags().Bool("clean-statusbar", false, "Apply clean status bar overrides during the capture and restore the previous ones afterwards")
+	screenshotCmd.Flags().Bool("frame", false, "Composite the screenshot into a device frame")
+	screenshotCmd.Flags().String("frame-background", "", "Background around the frame: transparent or a #rrggbb color (used with --frame)")
+	screenshotCmd.Flags().Int("frame-padding", 0, "Pixels of background around the frame (used with --frame)")
 
 	// recordCmd flags
 	recordCmd.Flags().IntP("duration", "d", 0, "Duration of the recording in seconds (default: unlimited)")
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// earthRadiusMeters is the mean Earth radius used for distance calculations.
+const earthRadiusMeters = 6371000.0
+
+// Waypoint is a single point on a simulated route.
+type Waypoint struct {
+	Lat  float64
+	Lon  float64
+	Time time.Time // Zero when the source has no timestamps.
+}
+
+// RouteSample is an interpolated position at an offset from the start of playback.
+type RouteSample struct {
+	Offset time.Duration
+	Lat    float64
+	Lon    float64
+}
+
+// ParseRouteFile reads waypoints from a .gpx or .kml file.
+func ParseRouteFile(path string) ([]Waypoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open route file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var points []Waypoint
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gpx":
+		points, err = ParseGPX(f)
+	case ".kml":
+		points, err = ParseKML(f)
+	default:
+		return nil, fmt.Errorf("%w: %s (expected .gpx or .kml)", ErrInvalidRoute, filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%w: %s contains no waypoints", ErrInvalidRoute, path)
+	}
+
+	return points, nil
+}
+
+// ParseGPX extracts track, route and waypoint points from a GPX document in document order.
+func ParseGPX(r io.Reader) ([]Waypoint, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		points  []Waypoint
+		current *Waypoint
+		inTime  bool
+	)
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return points, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRoute, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "trkpt", "rtept", "wpt":
+				wp, err := gpxPoint(t.Attr)
+				if err != nil {
+					return nil, err
+				}
+				current = &wp
+			case "time":
+				inTime = current != nil
+			}
+		case xml.CharData:
+			if inTime {
+				ts, err := time.Parse(time.RFC3339, strings.TrimSpace(string(t)))
+				if err != nil {
+					return nil, fmt.Errorf("%w: invalid time %q", ErrInvalidRoute, strings.TrimSpace(string(t)))
+				}
+				current.Time = ts
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "trkpt", "rtept", "wpt":
+				if current != nil {
+					points = append(points, *current)
+					current = nil
+				}
+			case "time":
+				inTime = false
+			}
+		}
+	}
+}
+
+func gpxPoint(attrs []xml.Attr) (Waypoint, error) {
+	var (
+		wp             Waypoint
+		hasLat, hasLon bool
+	)
+
+	for _, a := range attrs {
+		v, err := strconv.ParseFloat(a.Value, 64)
+		switch a.Name.Local {
+		case "lat":
+			wp.Lat, hasLat = v, err == nil
+		case "lon":
+			wp.Lon, hasLon = v, err == nil
+		}
+	}
+
+	if !hasLat || !hasLon {
+		return wp, fmt.Errorf("%w: point is missing lat/lon", ErrInvalidRoute)
+	}
+
+	return wp, ValidateCoordinate(wp.Lat, wp.Lon)
+}
+
+// ParseKML extracts points from every <coordinates> element and gx:Track of a KML document.
+// KML coordinates are written as "lon,lat[,alt]" tuples separated by whitespace.
+func ParseKML(r io.Reader) ([]Waypoint, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		points []Waypoint
+		field  string
+		when   []time.Time
+		text   strings.Builder
+	)
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return points, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRoute, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "coordinates", "coord", "when":
+				field = t.Name.Local
+				text.Reset()
+			case "Track":
+				when = nil
+			}
+		case xml.CharData:
+			if field != "" {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Local != field {
+				continue
+			}
+			field = ""
+
+			switch t.Name.Local {
+			case "coordinates":
+				for tuple := range strings.FieldsSeq(text.String()) {
+					wp, err := kmlPoint(strings.Split(tuple, ","))
+					if err != nil {
+						return nil, err
+					}
+					points = append(points, wp)
+				}
+			case "when":
+				ts, err := time.Parse(time.RFC3339, strings.TrimSpace(text.String()))
+				if err != nil {
+					return nil, fmt.Errorf("%w: invalid time %q", ErrInvalidRoute, strings.TrimSpace(text.String()))
+				}
+				when = append(when, ts)
+			case "coord":
+				// gx:coord uses spaces instead of commas.
+				wp, err := kmlPoint(strings.Fields(text.String()))
+				if err != nil {
+					return nil, err
+				}
+				if len(when) > 0 {
+					wp.Time = when[0]
+					when = when[1:]
+				}
+				points = append(points, wp)
+			}
+		}
+	}
+}
+
+func kmlPoint(parts []string) (Waypoint, error) {
+	if len(parts) < 2 {
+		return Waypoint{}, fmt.Errorf("%w: invalid coordinate %q", ErrInvalidRoute, strings.Join(parts, ","))
+	}
+
+	lon, errLon := strconv.ParseFloat(parts[0], 64)
+	lat, errLat := strconv.ParseFloat(parts[1], 64)
+	if errLon != nil || errLat != nil {
+		return Waypoint{}, fmt.Errorf("%w: invalid coordinate %q", ErrInvalidRoute, strings.Join(parts, ","))
+	}
+
+	return Waypoint{Lat: lat, Lon: lon}, ValidateCoordinate(lat, lon)
+}
+
+// ValidateCoordinate returns an error if lat or lon is out of range.
+func ValidateCoordinate(lat, lon float64) error {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 || math.IsNaN(lat) || math.IsNaN(lon) {
+		return fmt.Errorf("%w: %g,%g", ErrInvalidCoordinate, lat, lon)
+	}
+
+	return nil
+}
+
+// DistanceMeters returns the great-circle distance between two waypoints.
+func DistanceMeters(a, b Waypoint) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// hasTimestamps reports whether every waypoint has a timestamp and time never goes backwards.
+func hasTimestamps(points []Waypoint) bool {
+	for i, p := range points {
+		if p.Time.IsZero() || (i > 0 && p.Time.Before(points[i-1].Time)) {
+			return false
+		}
+	}
+
+	return len(points) > 1 && points[len(points)-1].Time.After(points[0].Time)
+}
+
+// segmentDurations returns how long playback spends travelling to each waypoint from
+// the previous one. Recorded timestamps are used when present; otherwise the duration
+// is derived from speedMPS. Both are divided by multiplier.
+func segmentDurations(points []Waypoint, speedMPS, multiplier float64) []time.Duration {
+	durations := make([]time.Duration, len(points))
+	useTimes := hasTimestamps(points)
+
+	for i := 1; i < len(points); i++ {
+		var seconds float64
+		if useTimes {
+			seconds = points[i].Time.Sub(points[i-1].Time).Seconds()
+		} else {
+			seconds = DistanceMeters(points[i-1], points[i]) / speedMPS
+		}
+		durations[i] = time.Duration(seconds / multiplier * float64(time.Second))
+	}
+
+	return durations
+}
+
+// RouteAverageSpeed returns the playback speed in meters per second for a route,
+// taking recorded timestamps and the speed multiplier into account.
+func RouteAverageSpeed(points []Waypoint, speedMPS, multiplier float64) float64 {
+	var (
+		distance float64
+		total    time.Duration
+	)
+
+	durations := segmentDurations(points, speedMPS, multiplier)
+	for i := 1; i < len(points); i++ {
+		distance += DistanceMeters(points[i-1], points[i])
+		total += durations[i]
+	}
+
+	if total <= 0 {
+		return speedMPS * multiplier
+	}
+
+	return distance / total.Seconds()
+}
+
+// InterpolateRoute returns positions along the route every step, starting at the first
+// waypoint and always ending at the last one.
+func InterpolateRoute(points []Waypoint, speedMPS, multiplier float64, step time.Duration) []RouteSample {
+	if len(points) == 0 || step <= 0 {
+		return nil
+	}
+
+	durations := segmentDurations(points, speedMPS, multiplier)
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+
+	samples := make([]RouteSample, 0, int(total/step)+2)
+	seg := 1
+	var segStart time.Duration
+
+	for offset := time.Duration(0); offset < total; offset += step {
+		// Advance to the segment containing offset.
+		for seg < len(points)-1 && offset >= segStart+durations[seg] {
+			segStart += durations[seg]
+			seg++
+		}
+
+		a, b := points[seg-1], points[seg]
+		frac := 0.0
+		if durations[seg] > 0 {
+			frac = float64(offset-segStart) / float64(durations[seg])
+		}
+
+		samples = append(samples, RouteSample{
+			Offset: offset,
+			Lat:    a.Lat + (b.Lat-a.Lat)*frac,
+			Lon:    a.Lon + (b.Lon-a.Lon)*frac,
+		})
+	}
+
+	last := points[len(points)-1]
+
+	return append(samples, RouteSample{Offset: total, Lat: last.Lat, Lon: last.Lon})
+}
+
+// ParseSpeedMultiplier parses a playback speed such as "2x", "0.5x" or "3".
+func ParseSpeedMultiplier(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "x"), 64)
+	if err != nil || v <= 0 || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%w: %q (expected a positive multiplier such as 2x)", ErrInvalidSpeed, s)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,665 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// seedAndroidMediaDir is where 'sim seed' puts photos and videos on Android.
+	seedAndroidMediaDir = "/sdcard/DCIM/Seed"
+	// seedAndroidContactsDir is where vCards are pushed before Android imports them.
+	seedAndroidContactsDir = "/sdcard/Download"
+	// seedAndroidStatePath records what was seeded on an Android device; wiping data removes it.
+	seedAndroidStatePath = "/data/local/tmp/sim-cli-seed.json"
+	// seedIOSStateFile records what was seeded on an iOS simulator, inside its data
+	// directory so that erasing the simulator removes it.
+	seedIOSStateFile = "sim-cli-seed.json"
+)
+
+// SeedManifest lists the data 'sim seed' puts on a device. Relative paths are resolved
+// against the manifest's directory.
+type SeedManifest struct {
+	Device      string            `yaml:"device"`      // Used when no device is given.
+	App         string            `yaml:"app"`         // Target of permissions; defaults to the configured defaultApp.
+	Media       []string          `yaml:"media"`       // Photos and videos: files, directories or globs.
+	Contacts    []string          `yaml:"contacts"`    // vCard files.
+	Files       []SeedFiles       `yaml:"files"`       // Files copied into app storage.
+	Permissions map[string]string `yaml:"permissions"` // Service name to grant, revoke or reset.
+	Deeplinks   []string          `yaml:"deeplinks"`   // URLs or saved @names, opened once.
+
+	dir string
+}
+
+// SeedFiles copies a host file or directory into a directory on each platform.
+type SeedFiles struct {
+	Src     string `yaml:"src"`
+	IOS     string `yaml:"ios"`     // app:<bundle-id>[:<container>]/<dir>
+	Android string `yaml:"android"` // Absolute device directory.
+}
+
+// SeedOptions controls 'sim seed'.
+type SeedOptions struct {
+	Force bool // Repeat media, contacts and deeplinks that were already seeded.
+}
+
+var seedCmd = &cobra.Command{
+	Use:   "seed [device-name-or-udid] <seed.yaml>",
+	Short: "Seed a device with media, contacts, files and permissions",
+	Long: `Apply a seed manifest to a device so every fresh device starts with the same data.
+
+Media is added with 'simctl addmedia' on iOS, and pushed to /sdcard/DCIM/Seed followed
+by a media scanner broadcast on Android. Contacts are vCards, added with addmedia on
+iOS and imported through the Contacts app on Android. Files are synced into app
+storage, permissions are applied and deeplinks are opened.
+
+Runs are idempotent: files are only copied when they changed, the media, iOS contacts
+and deeplinks already seeded are recorded on the device and skipped, and Android
+contacts whose names are already in the Contacts app are not imported again. Use
+--force to repeat them.
+
+Example manifest:
+  app: com.example.app
+  media:
+    - photos/*.jpg
+    - videos/intro.mp4
+  contacts:
+    - contacts/team.vcf
+  files:
+    - src: fixtures/
+      ios: app:com.example.app/Documents/fixtures
+      android: /sdcard/Android/data/com.example.app/files/fixtures
+  permissions:
+    photos: grant
+    contacts: grant
+  deeplinks:
+    - myapp://login?user=qa
+    - "@onboarding-done"`,
+	ValidArgsFunction: validDeviceAndFileArgs,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID, manifestPath string
+		if len(args) == 1 {
+			manifestPath = args[0]
+		} else {
+			deviceID, manifestPath = args[0], args[1]
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+
+		return Seed(deviceID, manifestPath, SeedOptions{Force: force})
+	},
+}
+
+func init() {
+	seedCmd.Flags().Bool("force", false, "Repeat media, contacts and deeplinks that were already seeded")
+}
+
+// LoadSeedManifest reads and validates a seed manifest.
+func LoadSeedManifest(manifestPath string) (*SeedManifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed manifest: %w", err)
+	}
+
+	var m SeedManifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidSeedManifest, manifestPath, err)
+	}
+	m.dir = filepath.Dir(manifestPath)
+
+	if m.App != "" {
+		if err := validateAppID(m.App); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSeedManifest, err)
+		}
+	}
+	for s, action := range m.Permissions {
+		if _, ok := privacyServices[s]; !ok && s != privacyAll {
+			return nil, fmt.Errorf("%w: %w: %q", ErrInvalidSeedManifest, ErrUnknownPrivacyService, s)
+		}
+		if err := validatePrivacyAction(action); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSeedManifest, err)
+		}
+	}
+	for _, f := range m.Files {
+		switch {
+		case f.Src == "":
+			return nil, fmt.Errorf("%w: files entry without src", ErrInvalidSeedManifest)
+		case f.IOS == "" && f.Android == "":
+			return nil, fmt.Errorf("%w: files entry %s has no ios or android destination", ErrInvalidSeedManifest, f.Src)
+		case f.IOS != "" && !IsAppPath(f.IOS):
+			return nil, fmt.Errorf("%w: ios destination %q must be an app: path", ErrInvalidSeedManifest, f.IOS)
+		case f.Android != "" && !path.IsAbs(f.Android):
+			return nil, fmt.Errorf("%w: android destination %q must be absolute", ErrInvalidSeedManifest, f.Android)
+		}
+	}
+
+	return &m, nil
+}
+
+// resolve returns p relative to the manifest's directory.
+func (m *SeedManifest) resolve(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+
+	return filepath.Join(m.dir, p)
+}
+
+// hostFiles expands the files, directories and globs in paths into regular files.
+func (m *SeedManifest) hostFiles(paths []string) ([]string, error) {
+	resolved := make([]string, len(paths))
+	for i, p := range paths {
+		resolved[i] = m.resolve(p)
+	}
+
+	expanded, err := expandLocalPaths(resolved)
+	if err != nil {
+		return nil, err
+	}
+	files, err := planCopy(expanded, "", true, filepath.Join)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.src
+	}
+
+	return names, nil
+}
+
+// Seed applies the manifest at manifestPath to a device. Every step is attempted and
+// reported; the returned error says how many failed. Pass an empty deviceID to use the
+// manifest's device or the first running one.
+func Seed(deviceID, manifestPath string, opts SeedOptions) error {
+	m, err := LoadSeedManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	if deviceID == "" {
+		deviceID = m.Device
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+	if !isAndroid && runtime.GOOS != DarwinOS {
+		return ErrIOSMacOnly
+	}
+
+	if m.App == "" && len(m.Permissions) > 0 {
+		if config, err := LoadConfig(); err == nil {
+			m.App = config.DefaultApp
+		}
+	}
+
+	state, err := loadSeedState(udid, isAndroid)
+	if err != nil {
+		return err
+	}
+
+	PrintInfo(fmt.Sprintf("Seeding '%s' from %s...", name, manifestPath))
+
+	s := &seeder{manifest: m, udid: udid, isAndroid: isAndroid, state: state, force: opts.Force}
+	steps := []struct {
+		name  string
+		count int
+		run   func() (string, error)
+	}{
+		{"media", len(m.Media), s.media},
+		{"contacts", len(m.Contacts), s.contacts},
+		{"files", len(m.Files), s.files},
+		{"permissions", len(m.Permissions), s.permissions},
+		{"deeplinks", len(m.Deeplinks), s.deeplinks},
+	}
+
+	failed, ran := 0, 0
+	for _, step := range steps {
+		if step.count == 0 {
+			continue
+		}
+		ran++
+
+		status, err := step.run()
+		if saveErr := state.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+		if err != nil {
+			failed++
+			PrintError(fmt.Sprintf("%s: %v", step.name, err))
+
+			continue
+		}
+		PrintSuccess(step.name + ": " + status)
+	}
+
+	if ran == 0 {
+		PrintInfo("The manifest lists nothing to seed.")
+		return nil
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d steps failed", ErrSeedFailed, failed, ran)
+	}
+
+	PrintSuccess(fmt.Sprintf("Seeded '%s'.", name))
+
+	return nil
+}
+
+// seeder runs the steps of a seed manifest on one device.
+type seeder struct {
+	manifest  *SeedManifest
+	udid      string
+	isAndroid bool
+	state     *seedState
+	force     bool
+}
+
+func (s *seeder) media() (string, error) {
+	files, err := s.manifest.hostFiles(s.manifest.Media)
+	if err != nil {
+		return "", err
+	}
+
+	if !s.isAndroid {
+		return s.addIOSMedia("media", files)
+	}
+
+	local := SyncManifest{}
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+		name := filepath.Base(f)
+		if _, dup := local[name]; dup {
+			return "", fmt.Errorf("%w: more than one media file is named %s", ErrInvalidSeedManifest, name)
+		}
+		if local[name], err = hostSyncEntry(f, info, false); err != nil {
+			return "", err
+		}
+	}
+
+	mediaSync := &syncer{
+		local:  func() (SyncManifest, error) { return local, nil },
+		target: androidSyncTarget{udid: s.udid, root: seedAndroidMediaDir},
+	}
+	res, err := mediaSync.pass()
+	if err != nil {
+		return "", err
+	}
+
+	for _, rel := range res.pushed {
+		uri := "file://" + path.Join(seedAndroidMediaDir, rel)
+		if out, err := packageExecutor.Output(CmdAdb, "-s", s.udid, "shell", "am", "broadcast",
+			"-a", "android.intent.action.MEDIA_SCANNER_SCAN_FILE", "-d", shellQuote(uri)); err != nil {
+			return "", fmt.Errorf("media scan of %s failed: %w\nOutput: %s", rel, err, string(out))
+		}
+	}
+
+	return seedCounts(len(res.pushed), len(files)-len(res.pushed), "added"), nil
+}
+
+func (s *seeder) contacts() (string, error) {
+	files, err := s.manifest.hostFiles(s.manifest.Contacts)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		if !strings.EqualFold(filepath.Ext(f), ".vcf") {
+			return "", fmt.Errorf("%w: contacts must be .vcf files: %s", ErrInvalidSeedManifest, f)
+		}
+	}
+
+	if !s.isAndroid {
+		return s.addIOSMedia("contact", files)
+	}
+
+	// The import only happens once the user confirms it in the Contacts app, so the
+	// device's contacts are checked instead of the seed record.
+	existing := androidContactNames(s.udid)
+
+	requested := 0
+	for _, f := range files {
+		names, err := vCardNames(f)
+		if err != nil {
+			return "", err
+		}
+		if len(names) > 0 && !s.force && !slices.ContainsFunc(names, func(n string) bool { return !existing[n] }) {
+			continue
+		}
+
+		remote := path.Join(seedAndroidContactsDir, filepath.Base(f))
+		if err := packageExecutor.Run(CmdAdb, "-s", s.udid, "push", f, remote); err != nil {
+			return "", fmt.Errorf("failed to push %s: %w", f, err)
+		}
+
+		out, err := packageExecutor.Output(CmdAdb, "-s", s.udid, "shell", "am", "start",
+			"-a", "android.intent.action.VIEW", "-d", shellQuote("file://"+remote), "-t", "text/x-vcard")
+		if err != nil {
+			return "", fmt.Errorf("failed to import %s: %w\nOutput: %s", f, err, string(out))
+		}
+		if _, err := parseAmStartOutput(string(out)); err != nil {
+			return "", fmt.Errorf("failed to import %s: %w", f, err)
+		}
+
+		requested++
+	}
+
+	status := seedCounts(requested, len(files)-requested, "import requested")
+	if requested > 0 {
+		status += " (confirm the import in the Contacts app)"
+	}
+
+	return status, nil
+}
+
+// vCardNames returns the formatted names (FN) of the contacts in a vCard file.
+func vCardNames(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	var names []string
+	for line := range strings.SplitSeq(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimRight(line, "\r"), ":")
+		if !ok {
+			continue
+		}
+		// Parameters such as FN;CHARSET=UTF-8 follow the property name.
+		if name, _, _ := strings.Cut(key, ";"); strings.EqualFold(name, "FN") && value != "" {
+			names = append(names, value)
+		}
+	}
+
+	return names, nil
+}
+
+// androidContactNames returns the display names of the contacts on an Android device.
+// It returns an empty set when the contacts provider cannot be queried.
+func androidContactNames(udid string) map[string]bool {
+	names := map[string]bool{}
+
+	out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "content", "query",
+		"--uri", "content://com.android.contacts/raw_contacts", "--projection", "display_name",
+		"--where", shellQuote("deleted=0"))
+	if err != nil {
+		return names
+	}
+
+	// Rows look like "Row: 0 display_name=Jane Doe".
+	for line := range strings.SplitSeq(string(out), "\n") {
+		if _, name, ok := strings.Cut(strings.TrimSpace(line), "display_name="); ok && name != "NULL" {
+			names[name] = true
+		}
+	}
+
+	return names
+}
+
+// addIOSMedia adds the files not seeded yet with 'simctl addmedia', which takes photos,
+// videos and vCards.
+func (s *seeder) addIOSMedia(kind string, files []string) (string, error) {
+	var pending, keys []string
+	for _, f := range files {
+		key, err := s.fileKey(kind, f)
+		if err != nil {
+			return "", err
+		}
+		if s.state.has(key) && !s.force {
+			continue
+		}
+		pending = append(pending, f)
+		keys = append(keys, key)
+	}
+
+	if len(pending) > 0 {
+		out, err := packageExecutor.Output(CmdXCrun, append([]string{CmdSimctl, "addmedia", s.udid}, pending...)...)
+		if err != nil {
+			return "", fmt.Errorf("simctl addmedia failed: %w\nOutput: %s", err, string(out))
+		}
+		for _, key := range keys {
+			s.state.mark(key)
+		}
+	}
+
+	return seedCounts(len(pending), len(files)-len(pending), "added"), nil
+}
+
+func (s *seeder) files() (string, error) {
+	var copied, unchanged, skipped int
+	for _, entry := range s.manifest.Files {
+		dest := entry.Android
+		if !s.isAndroid {
+			dest = entry.IOS
+		}
+		if dest == "" {
+			skipped++
+			continue
+		}
+
+		src := s.manifest.resolve(entry.Src)
+		info, err := os.Stat(src)
+		if err != nil {
+			return "", err
+		}
+
+		target, err := newSyncTarget(s.udid, s.isAndroid, dest)
+		if err != nil {
+			return "", err
+		}
+
+		local := func() (SyncManifest, error) { return hostSyncManifest(src, false) }
+		if !info.IsDir() {
+			local = func() (SyncManifest, error) {
+				entry, err := hostSyncEntry(src, info, false)
+				return SyncManifest{filepath.Base(src): entry}, err
+			}
+		}
+
+		fileSync := &syncer{local: local, target: target}
+		res, err := fileSync.pass()
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", entry.Src, err)
+		}
+
+		manifest, _ := local()
+		copied += len(res.pushed)
+		unchanged += len(manifest) - len(res.pushed)
+	}
+
+	status := seedCounts(copied, unchanged, "copied")
+	if skipped > 0 {
+		status += fmt.Sprintf(", %d without a destination for this platform", skipped)
+	}
+
+	return status, nil
+}
+
+func (s *seeder) permissions() (string, error) {
+	if s.manifest.App == "" {
+		return "", fmt.Errorf("%w: set app in the manifest or 'sim config set defaultApp'", ErrInvalidAppID)
+	}
+
+	services := make([]string, 0, len(s.manifest.Permissions))
+	for svc := range s.manifest.Permissions {
+		services = append(services, svc)
+	}
+	sort.Strings(services)
+
+	// Permission changes are idempotent, so they are applied on every run.
+	var unsupported []string
+	for _, svc := range services {
+		err := applyPrivacy(s.udid, s.isAndroid, s.manifest.Permissions[svc], s.manifest.App, svc)
+		if errors.Is(err, ErrPrivacyUnsupported) {
+			unsupported = append(unsupported, svc)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", svc, err)
+		}
+	}
+
+	status := fmt.Sprintf("%d applied to %s", len(services)-len(unsupported), s.manifest.App)
+	if len(unsupported) > 0 {
+		status += ", unsupported on this platform: " + strings.Join(unsupported, ", ")
+	}
+
+	return status, nil
+}
+
+func (s *seeder) deeplinks() (string, error) {
+	opened := 0
+	for _, target := range s.manifest.Deeplinks {
+		url, err := ExpandDeeplink(target, nil)
+		if err != nil {
+			return "", err
+		}
+
+		key := "deeplink:" + url
+		if s.state.has(key) && !s.force {
+			continue
+		}
+
+		if s.isAndroid {
+			args, err := AndroidIntent{}.StartArgs(url)
+			if err != nil {
+				return "", err
+			}
+			out, err := packageExecutor.Output(CmdAdb, append([]string{"-s", s.udid, "shell"}, args...)...)
+			if err != nil {
+				return "", fmt.Errorf("failed to open %s: %w\nOutput: %s", url, err, string(out))
+			}
+			if _, err := parseAmStartOutput(string(out)); err != nil {
+				return "", fmt.Errorf("failed to open %s: %w", url, err)
+			}
+		} else if out, err := packageExecutor.Output(CmdXCrun, CmdSimctl, "openurl", s.udid, url); err != nil {
+			return "", fmt.Errorf("failed to open %s: %w\nOutput: %s", url, err, string(out))
+		}
+
+		s.state.mark(key)
+		opened++
+	}
+
+	return seedCounts(opened, len(s.manifest.Deeplinks)-opened, "opened"), nil
+}
+
+// fileKey identifies a seeded file by its content, so renamed copies are not added twice.
+func (s *seeder) fileKey(kind, name string) (string, error) {
+	sum, err := hostSHA256(name)
+	if err != nil {
+		return "", err
+	}
+
+	return kind + ":" + sum, nil
+}
+
+// seedCounts formats a step status such as "2 added, 3 already present".
+func seedCounts(done, present int, verb string) string {
+	if done == 0 {
+		return fmt.Sprintf("all %d already present", present)
+	}
+	if present == 0 {
+		return fmt.Sprintf("%d %s", done, verb)
+	}
+
+	return fmt.Sprintf("%d %s, %d already present", done, verb, present)
+}
+
+// seedState is the record of seeded media, iOS contacts and deeplinks kept on the device.
+type seedState struct {
+	udid      string
+	isAndroid bool
+	hostPath  string // iOS only.
+	done      map[string]bool
+	dirty     bool
+}
+
+type seedStateFile struct {
+	Seeded []string `json:"seeded"`
+}
+
+// loadSeedState reads the device's seed record. A missing or unreadable record is empty.
+func loadSeedState(udid string, isAndroid bool) (*seedState, error) {
+	state := &seedState{udid: udid, isAndroid: isAndroid, done: map[string]bool{}}
+
+	var data []byte
+	if isAndroid {
+		data, _ = packageExecutor.Output(CmdAdb, "-s", udid, "shell", "cat", seedAndroidStatePath, "2>/dev/null")
+	} else {
+		out, err := packageExecutor.Output(CmdXCrun, CmdSimctl, "getenv", udid, "HOME")
+		home := strings.TrimSpace(string(out))
+		if err != nil || home == "" {
+			return nil, fmt.Errorf("failed to locate the simulator's data directory: %w", err)
+		}
+		state.hostPath = filepath.Join(home, seedIOSStateFile)
+		data, _ = os.ReadFile(state.hostPath)
+	}
+
+	var file seedStateFile
+	if json.Unmarshal(data, &file) == nil {
+		for _, key := range file.Seeded {
+			state.done[key] = true
+		}
+	}
+
+	return state, nil
+}
+
+func (s *seedState) has(key string) bool {
+	return s.done[key]
+}
+
+func (s *seedState) mark(key string) {
+	s.done[key] = true
+	s.dirty = true
+}
+
+// save writes the record back to the device if it changed.
+func (s *seedState) save() error {
+	if !s.dirty {
+		return nil
+	}
+
+	file := seedStateFile{Seeded: make([]string, 0, len(s.done))}
+	for key := range s.done {
+		file.Seeded = append(file.Seeded, key)
+	}
+	sort.Strings(file.Seeded)
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	if s.isAndroid {
+		if out, err := packageExecutor.Output(CmdAdb, "-s", s.udid, "shell",
+			"printf", "%s", shellQuote(string(data)), ">", seedAndroidStatePath); err != nil {
+			return fmt.Errorf("failed to record seed state: %w\nOutput: %s", err, string(out))
+		}
+	} else if err := os.WriteFile(s.hostPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to record seed state: %w", err)
+	}
+
+	s.dirty = false
+
+	return nil
+}
@@ -24,6 +24,7 @@ var statusCmd = &cobra.Command{
 		}
 
 		var rows [][]string
+		var hostProxy *string
 
 		for _, d := range running {
 			version := FormatRuntime(d.Runtime)
@@ -47,10 +48,25 @@ var statusCmd = &cobra.Command{
 				id = id[:8] + "..." + id[len(id)-4:]
 			}
 
-			rows = append(rows, []string{d.Name, platformStyled, version, state, id})
+			// iOS simulators share the Mac's proxy, so it is looked up once.
+			var proxy string
+			if d.Type == TypeAndroidEmulator {
+				proxy = GetAndroidProxy(d.UDID)
+			} else {
+				if hostProxy == nil {
+					p := GetHostProxy()
+					hostProxy = &p
+				}
+				proxy = *hostProxy
+			}
+			if proxy == "" {
+				proxy = "-"
+			}
+
+			rows = append(rows, []string{d.Name, platformStyled, version, state, id, proxy})
 		}
 
-		headers := []string{"Name", "Platform", "OS Version", "State", "ID"}
+		headers := []string{"Name", "Platform", "OS Version", "State", "ID", "Proxy"}
 		RenderTable(headers, rows)
 	},
 }
@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// androidDemoAction is the SystemUI demo mode broadcast action.
+	androidDemoAction = "com.android.systemui.demo"
+	// maxIOSWifiBars is the number of Wi-Fi bars shown by iOS; Android shows one more level.
+	maxIOSWifiBars = 3
+	maxAndroidWifi = 4
+	maxCellBars    = 4
+)
+
+// statusBarTimePattern matches the H:MM / HH:MM times that Android demo mode can display.
+var statusBarTimePattern = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+
+// StatusBarOverrides holds the values shown in the status bar while overridden.
+type StatusBarOverrides struct {
+	Time     string // Clock text, e.g. "9:41".
+	Battery  int    // Battery level, 0-100.
+	WiFi     int    // Wi-Fi bars, 0-3.
+	Cellular int    // Cellular bars, 0-4.
+	Charging bool   // Show the battery as charging.
+}
+
+// CleanStatusBar returns the overrides used for marketing screenshots.
+func CleanStatusBar() StatusBarOverrides {
+	return StatusBarOverrides{Time: "9:41", Battery: 100, WiFi: maxIOSWifiBars, Cellular: maxCellBars}
+}
+
+var statusBarCmd = &cobra.Command{
+	Use:     "statusbar",
+	Aliases: []string{"sb"},
+	Short:   "Override the status bar for clean screenshots",
+	Long: `Override the clock, battery and signal indicators in the status bar.
+
+iOS simulators use 'simctl status_bar'. Android emulators use SystemUI demo mode.`,
+}
+
+var statusBarOverrideCmd = &cobra.Command{
+	Use:               "override [device-name-or-udid]",
+	Short:             "Override status bar values",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		o := CleanStatusBar()
+		o.Time, _ = cmd.Flags().GetString("time")
+		o.Battery, _ = cmd.Flags().GetInt("battery")
+		o.WiFi, _ = cmd.Flags().GetInt("wifi")
+		o.Cellular, _ = cmd.Flags().GetInt("cellular")
+		o.Charging, _ = cmd.Flags().GetBool("charging")
+
+		return OverrideStatusBar(deviceID, o)
+	},
+}
+
+var statusBarClearCmd = &cobra.Command{
+	Use:               "clear [device-name-or-udid]",
+	Short:             "Restore the real status bar",
+	ValidArgsFunction: validDeviceArgs,
+	Args:              cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var deviceID string
+		if len(args) == 1 {
+			deviceID = args[0]
+		}
+
+		return ClearStatusBar(deviceID)
+	},
+}
+
+func init() {
+	clean := CleanStatusBar()
+	statusBarOverrideCmd.Flags().String("time", clean.Time, "Clock text (H:MM)")
+	statusBarOverrideCmd.Flags().Int("battery", clean.Battery, "Battery level (0-100)")
+	statusBarOverrideCmd.Flags().Int("wifi", clean.WiFi, "Wi-Fi bars (0-3)")
+	statusBarOverrideCmd.Flags().Int("cellular", clean.Cellular, "Cellular bars (0-4)")
+	statusBarOverrideCmd.Flags().Bool("charging", clean.Charging, "Show the battery as charging")
+
+	statusBarCmd.AddCommand(statusBarOverrideCmd)
+	statusBarCmd.AddCommand(statusBarClearCmd)
+}
+
+// Validate returns an error if any override value is out of range.
+func (o StatusBarOverrides) Validate() error {
+	switch {
+	case o.Battery < 0 || o.Battery > 100:
+		return fmt.Errorf("%w: battery must be 0-100, got %d", ErrInvalidStatusBar, o.Battery)
+	case o.WiFi < 0 || o.WiFi > maxIOSWifiBars:
+		return fmt.Errorf("%w: wifi must be 0-%d, got %d", ErrInvalidStatusBar, maxIOSWifiBars, o.WiFi)
+	case o.Cellular < 0 || o.Cellular > maxCellBars:
+		return fmt.Errorf("%w: cellular must be 0-%d, got %d", ErrInvalidStatusBar, maxCellBars, o.Cellular)
+	case !statusBarTimePattern.MatchString(o.Time):
+		return fmt.Errorf("%w: time must be H:MM, got %q", ErrInvalidStatusBar, o.Time)
+	}
+
+	return nil
+}
+
+// OverrideStatusBar applies o to the device. Pass an empty deviceID to use the first running device.
+func OverrideStatusBar(deviceID string, o StatusBarOverrides) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if err := applyStatusBar(udid, isAndroid, o); err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Status bar overridden on '%s'.", name))
+
+	return nil
+}
+
+// ClearStatusBar removes all status bar overrides. Pass an empty deviceID to use the first running device.
+func ClearStatusBar(deviceID string) error {
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if err := clearStatusBar(udid, isAndroid); err != nil {
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Status bar restored on '%s'.", name))
+
+	return nil
+}
+
+// applyStatusBar applies status bar overrides to a running device.
+func applyStatusBar(udid string, isAndroid bool, o StatusBarOverrides) error {
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+
+		args := []string{
+			CmdSimctl, "status_bar", udid, "override",
+			"--time", o.Time,
+		}
+		args = append(args, iosBatteryArgs(o.Battery, o.Charging)...)
+		args = append(args,
+			"--dataNetwork", "wifi",
+			"--wifiMode", "active",
+			"--wifiBars", strconv.Itoa(o.WiFi),
+			"--cellularMode", "active",
+			"--cellularBars", strconv.Itoa(o.Cellular),
+		)
+		if err := packageExecutor.Run(CmdXCrun, args...); err != nil {
+			return fmt.Errorf("failed to override iOS status bar: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := packageExecutor.Run(CmdAdb, "-s", udid, "shell", "settings", "put", "global", "sysui_demo_allowed", "1"); err != nil {
+		return fmt.Errorf("failed to enable SystemUI demo mode: %w", err)
+	}
+
+	for _, extras := range androidDemoCommands(o) {
+		if err := sendAndroidDemoCommand(udid, extras...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clearStatusBar restores the real status bar on a running device.
+func clearStatusBar(udid string, isAndroid bool) error {
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return ErrIOSMacOnly
+		}
+
+		if err := packageExecutor.Run(CmdXCrun, CmdSimctl, "status_bar", udid, "clear"); err != nil {
+			return fmt.Errorf("failed to clear iOS status bar: %w", err)
+		}
+
+		return nil
+	}
+
+	return sendAndroidDemoCommand(udid, "command", "exit")
+}
+
+// androidDemoCommands returns the demo mode broadcast extras that display o.
+func androidDemoCommands(o StatusBarOverrides) [][]string {
+	hhmm := strings.Replace(fmt.Sprintf("%05s", o.Time), ":", "", 1)
+	hhmm = strings.ReplaceAll(hhmm, " ", "0")
+	wifi := int(math.Round(float64(o.WiFi) * maxAndroidWifi / maxIOSWifiBars))
+
+	return [][]string{
+		{"command", "enter"},
+		{"command", "clock", "hhmm", hhmm},
+		{"command", "battery", "level", strconv.Itoa(o.Battery), "plugged", strconv.FormatBool(o.Charging)},
+		{"command", "network", "wifi", "show", "level", strconv.Itoa(wifi)},
+		{"command", "network", "mobile", "show", "datatype", "none", "level", strconv.Itoa(o.Cellular)},
+		{"command", "notifications", "visible", "false"},
+	}
+}
+
+// sendAndroidDemoCommand broadcasts a SystemUI demo command. extras are key/value pairs.
+func sendAndroidDemoCommand(udid string, extras ...string) error {
+	args := []string{"-s", udid, "shell", "am", "broadcast", "-a", androidDemoAction}
+	for i := 0; i+1 < len(extras); i += 2 {
+		args = append(args, "-e", extras[i], extras[i+1])
+	}
+
+	if err := packageExecutor.Run(CmdAdb, args...); err != nil {
+		return fmt.Errorf("failed to send SystemUI demo command %q: %w", extras[1], err)
+	}
+
+	return nil
+}
+
+// iosBatteryArgs returns the simctl status_bar flags for a battery level and charging state.
+func iosBatteryArgs(level int, charging bool) []string {
+	return []string{"--batteryLevel", strconv.Itoa(level), "--batteryState", iosBatteryState(level, charging)}
+}
+
+// iosBatteryState shows a full battery as charged rather than discharging.
+func iosBatteryState(level int, charging bool) string {
+	switch {
+	case level >= 100:
+		return "charged"
+	case charging:
+		return "charging"
+	default:
+		return "discharging"
+	}
+}
+
+// iosStatusBarEnums maps the enum numbers 'simctl status_bar list' prints back to the
+// names 'simctl status_bar override' accepts, in the order simctl's help lists them.
+var iosStatusBarEnums = map[string][]string{
+	"--dataNetwork":  {"hide", "wifi", "3g", "4g", "lte", "lte-a", "lte+", "5g", "5g+", "5g-uwb", "5g-uc"},
+	"--wifiMode":     {"", "searching", "failed", "active"},
+	"--cellularMode": {"notSupported", "searching", "failed", "active"},
+	"--batteryState": {"charging", "charged", "discharging"},
+}
+
+// iosStatusBarListKeys maps the fields of 'simctl status_bar list' to override flags.
+var iosStatusBarListKeys = map[string]string{
+	"Time":            "--time",
+	"DataNetworkType": "--dataNetwork",
+	"WiFi Mode":       "--wifiMode",
+	"WiFi Bars":       "--wifiBars",
+	"Cell Mode":       "--cellularMode",
+	"Cell Bars":       "--cellularBars",
+	"Operator Name":   "--operatorName",
+	"Battery State":   "--batteryState",
+	"Battery Level":   "--batteryLevel",
+}
+
+// ParseIOSStatusBarList converts 'simctl status_bar list' output into the override flags
+// that reproduce it, as flag/value pairs. It returns nil when nothing is overridden.
+func ParseIOSStatusBarList(output string) []string {
+	var args []string
+	for line := range strings.SplitSeq(output, "\n") {
+		// Related fields share a line: "WiFi Mode: 3, WiFi Bars: 3".
+		for field := range strings.SplitSeq(line, ", ") {
+			key, value, ok := strings.Cut(strings.TrimSpace(field), ": ")
+			flag, known := iosStatusBarListKeys[key]
+			if !ok || !known || value == "" {
+				continue
+			}
+
+			if names, isEnum := iosStatusBarEnums[flag]; isEnum {
+				if n, err := strconv.Atoi(value); err == nil {
+					if n < 0 || n >= len(names) || names[n] == "" {
+						continue
+					}
+					value = names[n]
+				}
+			}
+			args = append(args, flag, value)
+		}
+	}
+
+	return args
+}
+
+// withoutIOSBattery drops the battery flags from override flag/value pairs.
+func withoutIOSBattery(args []string) []string {
+	var kept []string
+	for i := 0; i+1 < len(args); i += 2 {
+		if args[i] != "--batteryState" && args[i] != "--batteryLevel" {
+			kept = append(kept, args[i], args[i+1])
+		}
+	}
+
+	return kept
+}
+
+// iosStatusBarOverrides returns the override flags currently in effect on a simulator.
+func iosStatusBarOverrides(udid string) ([]string, error) {
+	out, err := packageExecutor.Output(CmdXCrun, CmdSimctl, "status_bar", udid, "list")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read iOS status bar overrides: %w", err)
+	}
+
+	return ParseIOSStatusBarList(string(out)), nil
+}
+
+// setIOSStatusBarOverrides clears the status bar and applies args, if any.
+func setIOSStatusBarOverrides(udid string, args []string) error {
+	if err := clearStatusBar(udid, false); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	if err := packageExecutor.Run(CmdXCrun, append([]string{CmdSimctl, "status_bar", udid, "override"}, args...)...); err != nil {
+		return fmt.Errorf("failed to override iOS status bar: %w", err)
+	}
+
+	return nil
+}
+
+// statusBarSnapshot is the status bar state saved before a temporary override.
+type statusBarSnapshot struct {
+	iosOverrides []string // Override flags in effect on iOS; empty when none.
+	androidDemo  bool     // Android demo mode was already on.
+}
+
+// snapshotStatusBar records the current status bar overrides so they can be restored.
+func snapshotStatusBar(udid string, isAndroid bool) (statusBarSnapshot, error) {
+	if !isAndroid {
+		if runtime.GOOS != DarwinOS {
+			return statusBarSnapshot{}, ErrIOSMacOnly
+		}
+		args, err := iosStatusBarOverrides(udid)
+
+		return statusBarSnapshot{iosOverrides: args}, err
+	}
+
+	out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell", "settings", "get", "global", "sysui_tuner_demo_on")
+	if err != nil {
+		return statusBarSnapshot{}, fmt.Errorf("failed to read SystemUI demo mode: %w", err)
+	}
+
+	return statusBarSnapshot{androidDemo: strings.TrimSpace(string(out)) == "1"}, nil
+}
+
+// restoreStatusBar puts back the state recorded by snapshotStatusBar. On Android, demo mode
+// is only left when it was off before; its previous values cannot be read back.
+func restoreStatusBar(udid string, isAndroid bool, snap statusBarSnapshot) error {
+	if !isAndroid {
+		return setIOSStatusBarOverrides(udid, snap.iosOverrides)
+	}
+	if snap.androidDemo {
+		return nil
+	}
+
+	return clearStatusBar(udid, true)
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithoutIOSBattery(t *testing.T) {
+	args := []string{"--time", "9:41", "--batteryState", "charged", "--batteryLevel", "100", "--wifiBars", "3"}
+
+	if got := strings.Join(withoutIOSBattery(args), " "); got != "--time 9:41 --wifiBars 3" {
+		t.Errorf("withoutIOSBattery() = %q, want the clock and Wi-Fi overrides only", got)
+	}
+	if got := withoutIOSBattery([]string{"--batteryLevel", "20"}); got != nil {
+		t.Errorf("expected no overrides to re-apply, got %v", got)
+	}
+}
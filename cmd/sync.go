@@ -0,0 +1,542 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// syncDebounce is how long --watch waits for a burst of file events to settle.
+const syncDebounce = 300 * time.Millisecond
+
+// SyncOptions controls 'sim sync'.
+type SyncOptions struct {
+	Delete   bool // Remove remote files that no longer exist locally.
+	Checksum bool // Compare sha256 sums instead of size and modification time.
+	DryRun   bool // Print the changes without transferring anything.
+	Watch    bool // Keep syncing as local files change.
+}
+
+// SyncEntry describes one file in a sync manifest.
+type SyncEntry struct {
+	Size    int64
+	ModTime int64  // Unix seconds.
+	Sum     string // Hex sha256, only set with SyncOptions.Checksum.
+
+	path string // Host path of a local entry.
+}
+
+// SyncManifest maps slash-separated paths relative to a sync root to their entries.
+type SyncManifest map[string]SyncEntry
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <local-dir> [device-name-or-udid:]<remote-dir>",
+	Short: "Incrementally sync a local directory to a device",
+	Long: `Incrementally sync a local directory to a device.
+
+Only files that are new or changed are transferred. Files are compared by size and
+modification time, or by sha256 with --checksum. --delete removes remote files that
+no longer exist locally, and --watch keeps syncing as files are saved.
+
+The remote directory is an absolute device path on Android or an app: path on iOS.
+Prefix it with the device name and a colon to pick a device.
+
+Examples:
+  sim sync ./assets /sdcard/Android/data/com.example.app/files/assets
+  sim sync ./assets Pixel_7:/sdcard/Android/data/com.example.app/files/assets --delete --watch
+  sim sync ./fixtures "iPhone 15:app:com.example.app/Documents/fixtures" --checksum`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deviceID, remote, err := ParseSyncTarget(args[1])
+		if err != nil {
+			return err
+		}
+
+		var opts SyncOptions
+		opts.Delete, _ = cmd.Flags().GetBool("delete")
+		opts.Checksum, _ = cmd.Flags().GetBool("checksum")
+		opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		opts.Watch, _ = cmd.Flags().GetBool("watch")
+
+		return Sync(deviceID, args[0], remote, opts)
+	},
+}
+
+func init() {
+	syncCmd.Flags().Bool("delete", false, "Remove remote files that no longer exist locally")
+	syncCmd.Flags().Bool("checksum", false, "Compare sha256 sums instead of size and modification time")
+	syncCmd.Flags().BoolP("dry-run", "n", false, "Show what would change without transferring")
+	syncCmd.Flags().BoolP("watch", "w", false, "Keep syncing as local files change")
+}
+
+// ParseSyncTarget splits [device:]<remote-dir> into a device name and a remote directory.
+// The remote directory is an absolute path or an app: path; an empty device means the
+// first running one.
+func ParseSyncTarget(target string) (deviceID, remote string, err error) {
+	remote = target
+	if !strings.HasPrefix(target, "/") && !IsAppPath(target) {
+		var ok bool
+		deviceID, remote, ok = strings.Cut(target, ":")
+		if !ok || deviceID == "" {
+			return "", "", fmt.Errorf("remote directory %q must be absolute or an app: path, e.g. Pixel_7:/sdcard/assets", target) //nolint:err113
+		}
+	}
+
+	if !strings.HasPrefix(remote, "/") && !IsAppPath(remote) {
+		return "", "", fmt.Errorf("remote directory %q must be absolute or an app: path, e.g. Pixel_7:/sdcard/assets", remote) //nolint:err113
+	}
+
+	return deviceID, remote, nil
+}
+
+// DiffSyncManifests returns the local files missing or different on the remote side and
+// the remote files that no longer exist locally, both sorted. Entries with sums are
+// compared by sum, others by size and modification time.
+func DiffSyncManifests(local, remote SyncManifest) (changed, removed []string) {
+	for rel, l := range local {
+		r, ok := remote[rel]
+		switch {
+		case !ok, l.Size != r.Size:
+			changed = append(changed, rel)
+		case l.Sum != "" || r.Sum != "":
+			if l.Sum != r.Sum {
+				changed = append(changed, rel)
+			}
+		case l.ModTime != r.ModTime:
+			changed = append(changed, rel)
+		}
+	}
+
+	for rel := range remote {
+		if _, ok := local[rel]; !ok {
+			removed = append(removed, rel)
+		}
+	}
+
+	slices.Sort(changed)
+	slices.Sort(removed)
+
+	return changed, removed
+}
+
+// syncTarget is the remote side of a sync.
+type syncTarget interface {
+	// list returns the files under the remote root, hashed when checksum is set.
+	list(checksum bool) (SyncManifest, error)
+	// push copies the local entries rels to the remote root, keeping their modification times.
+	push(rels []string, local SyncManifest) (int64, error)
+	// remove deletes remote files.
+	remove(rels []string) error
+}
+
+// androidSyncTarget syncs into a directory on an Android device.
+type androidSyncTarget struct {
+	udid string
+	root string
+}
+
+func (t androidSyncTarget) list(checksum bool) (SyncManifest, error) {
+	root := shellQuote(t.root)
+	out, err := packageExecutor.Output(CmdAdb, "-s", t.udid, "shell",
+		"if [ -d "+root+" ]; then find "+root+" -type f -exec stat -c '%s %Y %n' {} +; fi")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s on device: %w", t.root, err)
+	}
+
+	manifest := SyncManifest{}
+	prefix := strings.TrimSuffix(t.root, "/") + "/"
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 3)
+		if len(fields) != 3 || !strings.HasPrefix(fields[2], prefix) {
+			continue
+		}
+		size, sizeErr := strconv.ParseInt(fields[0], 10, 64)
+		mtime, mtimeErr := strconv.ParseInt(fields[1], 10, 64)
+		if sizeErr != nil || mtimeErr != nil {
+			continue
+		}
+		manifest[strings.TrimPrefix(fields[2], prefix)] = SyncEntry{Size: size, ModTime: mtime}
+	}
+
+	if checksum && len(manifest) > 0 {
+		paths := make([]string, 0, len(manifest))
+		for rel := range manifest {
+			paths = append(paths, path.Join(t.root, rel))
+		}
+		sums, err := androidSHA256(t.udid, paths)
+		if err != nil {
+			return nil, err
+		}
+		for rel, entry := range manifest {
+			entry.Sum = sums[path.Join(t.root, rel)]
+			manifest[rel] = entry
+		}
+	}
+
+	return manifest, nil
+}
+
+func (t androidSyncTarget) push(rels []string, local SyncManifest) (int64, error) {
+	files := make([]transferFile, len(rels))
+	for i, rel := range rels {
+		files[i] = transferFile{
+			src:  local[rel].path,
+			dst:  path.Join(t.root, rel),
+			size: local[rel].Size,
+		}
+	}
+
+	// adb push sets the device file's modification time to the host file's.
+	return pushAndroidTransfer(t.udid, files)
+}
+
+func (t androidSyncTarget) remove(rels []string) error {
+	for start := 0; start < len(rels); start += shellPathBatch {
+		args := []string{"-s", t.udid, "shell", "rm", "-f"}
+		for _, rel := range rels[start:min(start+shellPathBatch, len(rels))] {
+			args = append(args, shellQuote(path.Join(t.root, rel)))
+		}
+		if out, err := packageExecutor.Output(CmdAdb, args...); err != nil {
+			return fmt.Errorf("failed to remove files on device: %w\nOutput: %s", err, string(out))
+		}
+	}
+
+	return nil
+}
+
+// hostSyncTarget syncs into a host directory, such as an iOS app container.
+type hostSyncTarget struct {
+	root string
+}
+
+func (t hostSyncTarget) list(checksum bool) (SyncManifest, error) {
+	if _, err := os.Stat(t.root); os.IsNotExist(err) {
+		return SyncManifest{}, nil
+	}
+
+	return hostSyncManifest(t.root, checksum)
+}
+
+func (t hostSyncTarget) push(rels []string, local SyncManifest) (int64, error) {
+	files := make([]transferFile, len(rels))
+	for i, rel := range rels {
+		files[i] = transferFile{
+			src:  local[rel].path,
+			dst:  filepath.Join(t.root, filepath.FromSlash(rel)),
+			size: local[rel].Size,
+		}
+	}
+
+	total, err := copyHostTransfer(files)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, rel := range rels {
+		mtime := time.Unix(local[rel].ModTime, 0)
+		if err := os.Chtimes(filepath.Join(t.root, filepath.FromSlash(rel)), mtime, mtime); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+func (t hostSyncTarget) remove(rels []string) error {
+	for _, rel := range rels {
+		if err := os.Remove(filepath.Join(t.root, filepath.FromSlash(rel))); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hostSyncManifest lists the regular files under root.
+func hostSyncManifest(root string, checksum bool) (SyncManifest, error) {
+	manifest := SyncManifest{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		entry, err := hostSyncEntry(p, info, checksum)
+		if err != nil {
+			return err
+		}
+		manifest[filepath.ToSlash(rel)] = entry
+
+		return nil
+	})
+
+	return manifest, err
+}
+
+// hostSyncEntry describes the host file p with the given info.
+func hostSyncEntry(p string, info fs.FileInfo, checksum bool) (SyncEntry, error) {
+	entry := SyncEntry{Size: info.Size(), ModTime: info.ModTime().Unix(), path: p}
+	if checksum {
+		sum, err := hostSHA256(p)
+		if err != nil {
+			return SyncEntry{}, err
+		}
+		entry.Sum = sum
+	}
+
+	return entry, nil
+}
+
+// syncer applies one sync pass at a time, remembering the remote manifest between
+// passes so --watch does not list the device again after every save.
+type syncer struct {
+	local  func() (SyncManifest, error)
+	target syncTarget
+	opts   SyncOptions
+	remote SyncManifest
+}
+
+// syncResult lists what a sync pass changed.
+type syncResult struct {
+	pushed  []string
+	deleted []string
+	bytes   int64
+}
+
+// pass pushes changed files and, with Delete, removes stale ones. A dry run lists the
+// changes instead.
+func (s *syncer) pass() (syncResult, error) {
+	local, err := s.local()
+	if err != nil {
+		return syncResult{}, err
+	}
+
+	if s.remote == nil {
+		if s.remote, err = s.target.list(s.opts.Checksum); err != nil {
+			return syncResult{}, err
+		}
+	}
+
+	changed, removed := DiffSyncManifests(local, s.remote)
+	if !s.opts.Delete {
+		removed = nil
+	}
+
+	if s.opts.DryRun {
+		for _, rel := range changed {
+			fmt.Println("  push   " + rel)
+		}
+		for _, rel := range removed {
+			fmt.Println("  delete " + rel)
+		}
+
+		return syncResult{pushed: changed, deleted: removed}, nil
+	}
+
+	res := syncResult{pushed: changed, deleted: removed}
+	if len(changed) > 0 {
+		if res.bytes, err = s.target.push(changed, local); err != nil {
+			s.remote = nil
+			return syncResult{}, err
+		}
+		for _, rel := range changed {
+			s.remote[rel] = local[rel]
+		}
+	}
+	if len(removed) > 0 {
+		if err := s.target.remove(removed); err != nil {
+			s.remote = nil
+			return syncResult{}, err
+		}
+		for _, rel := range removed {
+			delete(s.remote, rel)
+		}
+	}
+
+	return res, nil
+}
+
+// run applies a pass and reports it.
+func (s *syncer) run() error {
+	res, err := s.pass()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(res.pushed) == 0 && len(res.deleted) == 0:
+		PrintInfo("Already up to date.")
+	case s.opts.DryRun:
+		PrintInfo(fmt.Sprintf("Dry run: %d file(s) to push, %d to delete.", len(res.pushed), len(res.deleted)))
+	default:
+		msg := fmt.Sprintf("Synced %d file(s) (%s)", len(res.pushed), formatBytes(res.bytes))
+		if len(res.deleted) > 0 {
+			msg += fmt.Sprintf(", deleted %d", len(res.deleted))
+		}
+		PrintSuccess(msg + ".")
+	}
+
+	return nil
+}
+
+// Sync copies new and changed files under localDir to remoteDir on a device, an absolute
+// path on Android or an app: path on iOS. Pass an empty deviceID to use the first
+// running device.
+func Sync(deviceID, localDir, remoteDir string, opts SyncOptions) error {
+	info, err := os.Stat(localDir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", localDir) //nolint:err113
+	}
+
+	udid, name, isAndroid, err := FindRunningDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	target, err := newSyncTarget(udid, isAndroid, remoteDir)
+	if err != nil {
+		return err
+	}
+
+	PrintInfo(fmt.Sprintf("Syncing %s to %s on '%s'...", localDir, remoteDir, name))
+
+	s := &syncer{
+		local: func() (SyncManifest, error) {
+			manifest, err := hostSyncManifest(localDir, opts.Checksum)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", localDir, err)
+			}
+
+			return manifest, nil
+		},
+		target: target,
+		opts:   opts,
+	}
+	if err := s.run(); err != nil {
+		return err
+	}
+
+	if !opts.Watch {
+		return nil
+	}
+
+	return watchSync(localDir, s.run)
+}
+
+// newSyncTarget returns the sync target for remoteDir, an absolute path on Android or an
+// app: path on iOS.
+func newSyncTarget(udid string, isAndroid bool, remoteDir string) (syncTarget, error) {
+	switch {
+	case IsAppPath(remoteDir):
+		if isAndroid {
+			return nil, fmt.Errorf("%w: app: paths address iOS app containers", ErrUnsupportedPlatform)
+		}
+
+		appPath, err := ParseAppPath(remoteDir)
+		if err != nil {
+			return nil, err
+		}
+		root, err := ResolveAppPath(udid, appPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return hostSyncTarget{root: root}, nil
+	case isAndroid:
+		if !path.IsAbs(remoteDir) {
+			return nil, fmt.Errorf("remote directory %q must be absolute", remoteDir) //nolint:err113
+		}
+
+		return androidSyncTarget{udid: udid, root: path.Clean(remoteDir)}, nil
+	default:
+		return nil, fmt.Errorf("%w: iOS sync targets are app:<bundle-id>/<path>", ErrInvalidAppPath)
+	}
+}
+
+// watchSync runs sync after each burst of file changes under root until interrupted.
+// Failed passes are reported and retried on the next change.
+func watchSync(root string, sync func() error) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", root, err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	if err := addWatchDirs(watcher, root); err != nil {
+		return err
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	PrintInfo(fmt.Sprintf("Watching %s for changes (Press Ctrl+C to stop)...", root))
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-sigChan:
+			PrintInfo("\nStopping sync...")
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = addWatchDirs(watcher, event.Name)
+				}
+			}
+			pending = time.After(syncDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			PrintError(fmt.Sprintf("Watch error: %v", err))
+		case <-pending:
+			pending = nil
+			if err := sync(); err != nil {
+				PrintError(err.Error())
+			}
+		}
+	}
+}
+
+// addWatchDirs watches root and every directory below it, since fsnotify is not recursive.
+func addWatchDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		if err := watcher.Add(p); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", p, err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,429 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+)
+
+const (
+	// defaultAndroidCopyDir is where 'copy to' puts files on Android without a destination.
+	defaultAndroidCopyDir = "/sdcard/Download/"
+	// progressPollSize is the file size above which transfers report progress while running.
+	progressPollSize = 4 << 20
+	// progressPollInterval is how often a running transfer's size is sampled.
+	progressPollInterval = 250 * time.Millisecond
+	// shellPathBatch bounds the number of paths passed to a single 'adb shell' command.
+	shellPathBatch = 64
+)
+
+// transferFile is a single file copied between the host and a device.
+type transferFile struct {
+	src  string
+	dst  string
+	size int64 // -1 when unknown.
+}
+
+// hasGlobMeta reports whether s contains glob metacharacters.
+func hasGlobMeta(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// expandLocalPaths expands glob patterns among paths. A pattern without matches is an error.
+func expandLocalPaths(patterns []string) ([]string, error) {
+	var paths []string
+	for _, p := range patterns {
+		if !hasGlobMeta(p) {
+			paths = append(paths, p)
+			continue
+		}
+
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", p) //nolint:err113
+		}
+		paths = append(paths, matches...)
+	}
+
+	return paths, nil
+}
+
+// planCopy lists the regular files under the host paths sources and where each goes
+// under dest. With intoDir, each source keeps its name inside dest; otherwise the single
+// source is copied to dest itself. join builds destination paths (path.Join for devices,
+// filepath.Join for the host).
+func planCopy(sources []string, dest string, intoDir bool, join func(...string) string) ([]transferFile, error) {
+	var files []transferFile
+	for _, src := range sources {
+		info, err := os.Stat(src)
+		if err != nil {
+			return nil, err
+		}
+
+		target := dest
+		if intoDir {
+			target = join(dest, filepath.Base(src))
+		}
+
+		if !info.IsDir() {
+			files = append(files, transferFile{src: src, dst: target, size: info.Size()})
+			continue
+		}
+
+		err = filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(src, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, transferFile{src: p, dst: join(target, filepath.ToSlash(rel)), size: fi.Size()})
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files to copy in %s", strings.Join(sources, ", ")) //nolint:err113
+	}
+
+	return files, nil
+}
+
+// remoteGlob quotes pattern for 'adb shell', leaving glob metacharacters unquoted so the
+// device shell expands them.
+func remoteGlob(pattern string) string {
+	var b strings.Builder
+	literal := ""
+	for _, r := range pattern {
+		if strings.ContainsRune("*?[]", r) {
+			if literal != "" {
+				b.WriteString(shellQuote(literal))
+				literal = ""
+			}
+			b.WriteRune(r)
+
+			continue
+		}
+		literal += string(r)
+	}
+	if literal != "" {
+		b.WriteString(shellQuote(literal))
+	}
+
+	return b.String()
+}
+
+// listAndroidFiles returns the sizes of the regular files matched by a device path, glob
+// or directory, keyed by path. It returns nil when the device cannot list them.
+func listAndroidFiles(udid, pattern string) map[string]int64 {
+	out, err := packageExecutor.Output(CmdAdb, "-s", udid, "shell",
+		"find", remoteGlob(pattern), "-type", "f", "-exec", "stat", "-c", "'%s %n'", "{}", "+")
+	if err != nil {
+		return nil
+	}
+
+	var files map[string]int64
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		sizeField, name, ok := strings.Cut(strings.TrimSpace(line), " ")
+		size, err := strconv.ParseInt(sizeField, 10, 64)
+		if !ok || err != nil || !strings.HasPrefix(name, "/") {
+			continue
+		}
+		if files == nil {
+			files = map[string]int64{}
+		}
+		files[name] = size
+	}
+
+	return files
+}
+
+// planAndroidPull maps the device files matched by pattern to host paths under dest.
+// It returns nil when the device cannot list the files.
+func planAndroidPull(udid, pattern, dest string, intoDir bool) []transferFile {
+	sizes := listAndroidFiles(udid, pattern)
+	if len(sizes) == 0 {
+		return nil
+	}
+
+	// Files keep their path relative to the directory holding the named file or directory,
+	// or the pattern's first glob, as 'cp -r' would.
+	root := strings.TrimSuffix(pattern, "/")
+	base := path.Dir(root)
+	if i := strings.IndexAny(root, "*?["); i >= 0 {
+		base = path.Dir(root[:i] + "x")
+		intoDir = true
+	}
+
+	names := make([]string, 0, len(sizes))
+	for name := range sizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := make([]transferFile, 0, len(names))
+	for _, name := range names {
+		dst := dest
+		switch {
+		case intoDir:
+			dst = filepath.Join(dest, filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(name, base), "/")))
+		case name != root:
+			dst = filepath.Join(dest, filepath.FromSlash(strings.TrimPrefix(name, root+"/")))
+		}
+		files = append(files, transferFile{src: name, dst: dst, size: sizes[name]})
+	}
+
+	return files
+}
+
+// androidSHA256 returns the sha256 of each device path, keyed by path.
+func androidSHA256(udid string, paths []string) (map[string]string, error) {
+	sums := make(map[string]string, len(paths))
+	for start := 0; start < len(paths); start += shellPathBatch {
+		batch := paths[start:min(start+shellPathBatch, len(paths))]
+
+		args := []string{"-s", udid, "shell", "sha256sum"}
+		for _, p := range batch {
+			args = append(args, shellQuote(p))
+		}
+
+		out, err := packageExecutor.Output(CmdAdb, args...)
+		if err != nil {
+			return nil, fmt.Errorf("sha256sum failed on device: %w", err)
+		}
+
+		for line := range strings.SplitSeq(string(out), "\n") {
+			sum, name, ok := strings.Cut(strings.TrimSpace(line), "  ")
+			if ok {
+				sums[name] = sum
+			}
+		}
+	}
+
+	return sums, nil
+}
+
+// hostSHA256 returns the hex sha256 of a host file.
+func hostSHA256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyTransfer compares the sha256 of each copied file on the host with the sum of
+// its device-side counterpart from deviceSums. push tells which side of files is the host.
+func verifyTransfer(files []transferFile, push bool, deviceSums func(paths []string) (map[string]string, error)) error {
+	hostPath := func(f transferFile) string { return f.dst }
+	devicePath := func(f transferFile) string { return f.src }
+	if push {
+		hostPath, devicePath = devicePath, hostPath
+	}
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = devicePath(f)
+	}
+
+	sums, err := deviceSums(paths)
+	if err != nil {
+		return err
+	}
+
+	var mismatched []string
+	for _, f := range files {
+		local, err := hostSHA256(hostPath(f))
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", hostPath(f), err)
+		}
+		if sums[devicePath(f)] != local {
+			mismatched = append(mismatched, devicePath(f))
+		}
+	}
+
+	if len(mismatched) > 0 {
+		return fmt.Errorf("%w: sha256 differs for %s", ErrVerifyFailed, strings.Join(mismatched, ", "))
+	}
+
+	PrintSuccess(fmt.Sprintf("Verified %d file(s) with sha256.", len(files)))
+
+	return nil
+}
+
+// hostSHA256Map hashes host paths for verifyTransfer.
+func hostSHA256Map(paths []string) (map[string]string, error) {
+	sums := make(map[string]string, len(paths))
+	for _, p := range paths {
+		sum, err := hostSHA256(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %w", p, err)
+		}
+		sums[p] = sum
+	}
+
+	return sums, nil
+}
+
+// transferProgress draws a progress bar over all bytes of a copy when stdout is a terminal.
+type transferProgress struct {
+	mu      sync.Mutex
+	bar     progress.Model
+	enabled bool
+	total   int64
+	done    int64 // Bytes of finished files.
+	current int64 // Bytes of the file in flight.
+	label   string
+}
+
+func newTransferProgress(files []transferFile) *transferProgress {
+	p := &transferProgress{bar: progress.New(progress.WithDefaultGradient(), progress.WithWidth(30))}
+	for _, f := range files {
+		p.total += max(f.size, 0)
+	}
+
+	info, err := os.Stdout.Stat()
+	p.enabled = err == nil && info.Mode()&os.ModeCharDevice != 0
+
+	return p
+}
+
+// start begins the transfer of the i-th of n files.
+func (p *transferProgress) start(i, n int, name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current = 0
+	p.label = fmt.Sprintf("%d/%d %s", i+1, n, name)
+	p.render()
+}
+
+// set records how many bytes of the current file have been copied.
+func (p *transferProgress) set(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current = n
+	p.render()
+}
+
+// finish marks the current file as done.
+func (p *transferProgress) finish(size int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done += max(size, 0)
+	p.current = 0
+	p.render()
+}
+
+// close clears the progress line.
+func (p *transferProgress) close() {
+	if p.enabled {
+		fmt.Print("\r\033[K")
+	}
+}
+
+func (p *transferProgress) render() {
+	if !p.enabled {
+		return
+	}
+
+	percent := 1.0
+	if p.total > 0 {
+		percent = min(float64(p.done+p.current)/float64(p.total), 1)
+	}
+	fmt.Printf("\r\033[K%s %s/%s  %s", p.bar.ViewAs(percent), formatBytes(p.done+p.current), formatBytes(p.total), p.label)
+}
+
+// watch samples size() while a large transfer runs, until the returned stop is called.
+func (p *transferProgress) watch(f transferFile, size func() int64) (stop func()) {
+	if !p.enabled || f.size < progressPollSize {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(progressPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				p.set(min(size(), f.size))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// progressWriter reports the bytes written through it.
+type progressWriter struct {
+	w       io.Writer
+	written int64
+	report  func(int64)
+}
+
+func (pw *progressWriter) Write(b []byte) (int, error) {
+	n, err := pw.w.Write(b)
+	pw.written += int64(n)
+	pw.report(pw.written)
+
+	return n, err
+}
+
+// formatBytes formats n bytes with a binary unit, e.g. "3.2 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -128,3 +128,8 @@ func convertToGIF(inputFile, outputFile string, fps, scale int) error {
 
 	return nil
 }
+
+// shellQuote quotes s for a POSIX shell, such as the one 'adb shell' runs commands in.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
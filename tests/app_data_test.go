@@ -0,0 +1,267 @@
+package tests
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+// androidDataTar builds a tar stream like the one produced by `run-as <pkg> tar -cf - .`.
+func androidDataTar(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	_ = tw.WriteHeader(&tar.Header{Name: "./", Typeflag: tar.TypeDir, Mode: 0o755})
+	_ = tw.WriteHeader(&tar.Header{Name: "./lib", Typeflag: tar.TypeSymlink, Linkname: "/data/app/com.example.app/lib/x86_64", Mode: 0o777})
+	_ = tw.WriteHeader(&tar.Header{Name: "./shared_prefs/", Typeflag: tar.TypeDir, Mode: 0o755})
+	content := []byte(`<map><boolean name="onboarded" value="true" /></map>`)
+	_ = tw.WriteHeader(&tar.Header{Name: "./shared_prefs/prefs.xml", Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))})
+	_, _ = tw.Write(content)
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to build tar: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func androidAppDataExecutor(t *testing.T, recorded *[][]string) *recordingExecutor {
+	t.Helper()
+
+	const emulatorSerial = "emulator-5554"
+	dataTar := androidDataTar(t)
+
+	return &recordingExecutor{
+		onOutput: func(name string, args []string) ([]byte, error) {
+			if name == "adb" {
+				joined := strings.Join(args, " ")
+				switch {
+				case joined == "devices":
+					return []byte("List of devices attached\n" + emulatorSerial + "\tdevice\n"), nil
+				case strings.Contains(joined, "avd name"):
+					return []byte("Pixel_7\nOK\n"), nil
+				case strings.Contains(joined, "dumpsys package"):
+					return []byte("Packages:\n    versionCode=42 minSdk=24\n    versionName=2.3.1\n"), nil
+				case strings.Contains(joined, "exec-out run-as"):
+					return dataTar, nil
+				}
+			}
+
+			return []byte{}, nil
+		},
+		onRun: func(name string, args []string) error {
+			*recorded = append(*recorded, append([]string{name}, args...))
+			return nil
+		},
+	}
+}
+
+func TestAppBackup_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded [][]string
+	cmd.SetExecutor(androidAppDataExecutor(t, &recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	out := filepath.Join(t.TempDir(), "state.tar.gz")
+	if err := cmd.BackupAppData("emulator-5554", "com.example.app", out); err != nil {
+		t.Fatalf("BackupAppData failed: %v", err)
+	}
+
+	manifest, err := cmd.ReadAppBackupManifest(out)
+	if err != nil {
+		t.Fatalf("ReadAppBackupManifest failed: %v", err)
+	}
+
+	if manifest.App != "com.example.app" {
+		t.Errorf("expected app com.example.app, got %s", manifest.App)
+	}
+	if manifest.Version != "2.3.1" {
+		t.Errorf("expected version 2.3.1, got %s", manifest.Version)
+	}
+	if manifest.Platform != cmd.PlatformAndroid {
+		t.Errorf("expected platform %s, got %s", cmd.PlatformAndroid, manifest.Platform)
+	}
+	if manifest.Device != "Pixel_7" || manifest.DeviceUDID != "emulator-5554" {
+		t.Errorf("unexpected device in manifest: %s (%s)", manifest.Device, manifest.DeviceUDID)
+	}
+}
+
+func TestAppRestore_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded [][]string
+	cmd.SetExecutor(androidAppDataExecutor(t, &recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	archive := filepath.Join(t.TempDir(), "state.tar.gz")
+	if err := cmd.BackupAppData("emulator-5554", "com.example.app", archive); err != nil {
+		t.Fatalf("BackupAppData failed: %v", err)
+	}
+
+	var pushed []string
+	exec := androidAppDataExecutor(t, &recorded)
+	exec.onRun = func(name string, args []string) error {
+		recorded = append(recorded, append([]string{name}, args...))
+		if len(args) >= 4 && args[2] == "push" {
+			// Inspect the staged archive before it is removed.
+			f, err := os.Open(args[3])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			tr := tar.NewReader(f)
+			for {
+				hdr, err := tr.Next()
+				if err != nil {
+					break
+				}
+				pushed = append(pushed, hdr.Name)
+			}
+		}
+
+		return nil
+	}
+	cmd.SetExecutor(exec)
+
+	if err := cmd.RestoreAppData("emulator-5554", "com.example.app", archive); err != nil {
+		t.Fatalf("RestoreAppData failed: %v", err)
+	}
+
+	var stopIdx, extractIdx = -1, -1
+	for i, call := range recorded {
+		joined := strings.Join(call, " ")
+		if strings.Contains(joined, "am force-stop com.example.app") {
+			stopIdx = i
+		}
+		if strings.Contains(joined, "run-as com.example.app tar -xf -") {
+			extractIdx = i
+		}
+	}
+
+	if stopIdx == -1 {
+		t.Error("expected app to be force-stopped before restore")
+	}
+	if extractIdx == -1 || extractIdx < stopIdx {
+		t.Error("expected archive to be extracted with run-as after stopping the app")
+	}
+
+	if !strings.Contains(strings.Join(pushed, ","), "shared_prefs/prefs.xml") {
+		t.Errorf("expected staged archive to contain shared_prefs/prefs.xml, got %v", pushed)
+	}
+	for _, name := range pushed {
+		if strings.HasPrefix(name, "data/") || name == "manifest.json" {
+			t.Errorf("staged archive should only contain container-relative paths, got %s", name)
+		}
+		if name == "lib" || strings.HasPrefix(name, "lib/") {
+			t.Errorf("staged archive should leave the system-owned lib link alone, got %s", name)
+		}
+	}
+}
+
+func TestAppRestore_MismatchedApp(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded [][]string
+	cmd.SetExecutor(androidAppDataExecutor(t, &recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	archive := filepath.Join(t.TempDir(), "state.tar.gz")
+	if err := cmd.BackupAppData("emulator-5554", "com.example.app", archive); err != nil {
+		t.Fatalf("BackupAppData failed: %v", err)
+	}
+
+	err := cmd.RestoreAppData("emulator-5554", "com.example.other", archive)
+	if !errors.Is(err, cmd.ErrBackupMismatch) {
+		t.Fatalf("expected ErrBackupMismatch, got %v", err)
+	}
+}
+
+func TestAppBackup_InvalidAppID(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	err := cmd.BackupAppData("", "com.example.app; rm -rf /", filepath.Join(t.TempDir(), "out.tar.gz"))
+	if !errors.Is(err, cmd.ErrInvalidAppID) {
+		t.Fatalf("expected ErrInvalidAppID, got %v", err)
+	}
+}
+
+// writeDataBackup writes a gzipped backup archive whose data/ entries are hdrs.
+func writeDataBackup(t *testing.T, hdrs ...*tar.Header) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, hdr := range hdrs {
+		hdr.Name = "data/" + hdr.Name
+		hdr.Mode = 0o644
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Size > 0 {
+			_, _ = tw.Write(bytes.Repeat([]byte("x"), int(hdr.Size)))
+		}
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+
+	p := filepath.Join(t.TempDir(), "state.tar.gz")
+	if err := os.WriteFile(p, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestExtractAppBackup_RejectsEscapes(t *testing.T) {
+	outside := t.TempDir()
+
+	tests := map[string][]*tar.Header{
+		"absolute symlink": {
+			{Name: "Library", Typeflag: tar.TypeSymlink, Linkname: outside},
+		},
+		"relative symlink": {
+			{Name: "Library", Typeflag: tar.TypeSymlink, Linkname: "Documents/../../outside"},
+		},
+		"file through symlink": {
+			{Name: "Documents/", Typeflag: tar.TypeDir},
+			{Name: "Library", Typeflag: tar.TypeSymlink, Linkname: "Documents"},
+			{Name: "Library/evil.txt", Typeflag: tar.TypeReg, Size: 4},
+		},
+	}
+
+	for name, hdrs := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := cmd.ExtractAppBackup(writeDataBackup(t, hdrs...), t.TempDir())
+			if !errors.Is(err, cmd.ErrInvalidBackup) {
+				t.Errorf("expected ErrInvalidBackup, got %v", err)
+			}
+		})
+	}
+
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Errorf("extraction wrote outside the destination: %v", entries)
+	}
+
+	dest := t.TempDir()
+	archive := writeDataBackup(t,
+		&tar.Header{Name: "Documents/", Typeflag: tar.TypeDir},
+		&tar.Header{Name: "Documents/db.sqlite", Typeflag: tar.TypeReg, Size: 3},
+		&tar.Header{Name: "current", Typeflag: tar.TypeSymlink, Linkname: "Documents/db.sqlite"},
+	)
+	if err := cmd.ExtractAppBackup(archive, dest); err != nil {
+		t.Fatalf("ExtractAppBackup failed: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, "current")); err != nil || string(data) != "xxx" {
+		t.Errorf("expected a symlink inside the container to be restored, got %q, %v", data, err)
+	}
+}
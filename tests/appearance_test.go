@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestSetAppearance_Android_NightMode(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SetAppearance("", "Dark"); err != nil {
+		t.Fatalf("SetAppearance failed: %v", err)
+	}
+	if err := cmd.SetAppearance("", "reset"); err != nil {
+		t.Fatalf("SetAppearance reset failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell cmd uimode night yes",
+		"-s emulator-5554 shell cmd uimode night no",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands: %v", recorded)
+	}
+}
+
+func TestSetAppearance_InvalidMode(t *testing.T) {
+	if err := cmd.SetAppearance("", "sepia"); !errors.Is(err, cmd.ErrInvalidSetting) {
+		t.Fatalf("expected ErrInvalidSetting, got %v", err)
+	}
+}
+
+func TestSetTextSize_Android_FontScale(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	for _, size := range []string{"extra-extra-large", "1.25", "reset"} {
+		if err := cmd.SetTextSize("", size); err != nil {
+			t.Fatalf("SetTextSize(%q) failed: %v", size, err)
+		}
+	}
+
+	const put = "-s emulator-5554 shell settings put system font_scale "
+	want := []string{put + "1.3", put + "1.25", put + "1"}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands: %v", recorded)
+	}
+
+	if err := cmd.SetTextSize("", "huge"); !errors.Is(err, cmd.ErrInvalidSetting) {
+		t.Errorf("expected ErrInvalidSetting, got %v", err)
+	}
+}
+
+func TestSetDisplay_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SetDisplay("", "1080x1920", 420); err != nil {
+		t.Fatalf("SetDisplay failed: %v", err)
+	}
+	if err := cmd.SetDisplay("", "reset", -1); err != nil {
+		t.Fatalf("SetDisplay reset failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell wm size 1080x1920",
+		"-s emulator-5554 shell wm density 420",
+		"-s emulator-5554 shell wm size reset",
+		"-s emulator-5554 shell wm density reset",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands: %v", recorded)
+	}
+}
+
+func TestSetDisplay_InvalidSize(t *testing.T) {
+	for _, size := range []string{"1080", "1080*1920", ""} {
+		if err := cmd.SetDisplay("", size, 0); !errors.Is(err, cmd.ErrInvalidSetting) {
+			t.Errorf("SetDisplay(%q) expected ErrInvalidSetting, got %v", size, err)
+		}
+	}
+}
+
+func TestResetDisplaySettings_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.ResetDisplaySettings(""); err != nil {
+		t.Fatalf("ResetDisplaySettings failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell cmd uimode night no",
+		"-s emulator-5554 shell settings put system font_scale 1",
+		"-s emulator-5554 shell wm size reset",
+		"-s emulator-5554 shell wm density reset",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands: %v", recorded)
+	}
+}
+
+func TestGetDisplaySettings_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	cmd.SetExecutor(&recordingExecutor{
+		onOutput: func(name string, args []string) ([]byte, error) {
+			switch strings.Join(args[3:], " ") {
+			case "cmd uimode night":
+				return []byte("Night mode: yes\n"), nil
+			case "settings get system font_scale":
+				return []byte("1.15\n"), nil
+			case "wm size":
+				return []byte("Physical size: 1080x2400\nOverride size: 720x1600\n"), nil
+			case "wm density":
+				return []byte("Physical density: 420\n"), nil
+			}
+
+			return []byte{}, nil
+		},
+	})
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	got := cmd.GetDisplaySettings("emulator-5554", true)
+	want := cmd.DisplaySettings{Appearance: "dark", TextSize: "1.15", Size: "720x1600", Density: "420"}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestTextSizeCategories_Ordered(t *testing.T) {
+	categories := cmd.TextSizeCategories()
+	if categories[0] != "extra-small" || categories[len(categories)-1] != "accessibility-extra-extra-extra-large" {
+		t.Errorf("unexpected category order: %v", categories)
+	}
+}
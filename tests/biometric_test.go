@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestSendBiometric_Android_MatchAndFail(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SendBiometric("", true, 2); err != nil {
+		t.Fatalf("SendBiometric match failed: %v", err)
+	}
+	if err := cmd.SendBiometric("", false, 99); err != nil {
+		t.Fatalf("SendBiometric fail failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 emu finger touch 2",
+		"-s emulator-5554 emu finger touch 99",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands: %v", recorded)
+	}
+}
+
+func TestEnrollBiometric_Android_GuidedTouches(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.EnrollBiometric("", "1234", 1, strings.NewReader("\n\nq\n\n")); err != nil {
+		t.Fatalf("EnrollBiometric failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell locksettings set-pin 1234",
+		"-s emulator-5554 shell am start -a android.settings.FINGERPRINT_ENROLL",
+		"-s emulator-5554 emu finger touch 1",
+		"-s emulator-5554 emu finger touch 1",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands:\n%s", strings.Join(recorded, "\n"))
+	}
+}
+
+func TestEnrollBiometric_Android_InvalidPIN(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.EnrollBiometric("", "12a", 1, strings.NewReader("")); !errors.Is(err, cmd.ErrInvalidSetting) {
+		t.Fatalf("expected ErrInvalidSetting, got %v", err)
+	}
+	if len(recorded) != 0 {
+		t.Errorf("expected no commands, got %v", recorded)
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+// testCAPEM is a self-signed CA whose OpenSSL subject_hash_old is e55f8d1f.
+const testCAPEM = `-----BEGIN CERTIFICATE-----
+MIIB0zCCAXmgAwIBAgIUdt4ZGoAKliIj2cqDQpSt8tvID0UwCgYIKoZIzj0EAwIw
+PjELMAkGA1UEBhMCVVMxFTATBgNVBAoMDFNpbSBDTEkgVGVzdDEYMBYGA1UEAwwP
+U2ltIENMSSBUZXN0IENBMCAXDTI2MTAxODE2MDU1MFoYDzIxMjYwOTI0MTYwNTUw
+WjA+MQswCQYDVQQGEwJVUzEVMBMGA1UECgwMU2ltIENMSSBUZXN0MRgwFgYDVQQD
+DA9TaW0gQ0xJIFRlc3QgQ0EwWTATBgcqhkjOPQIBBggqhkjOPQMBBwNCAATrvlMl
+9wHIXeG6+8kkpMHOxeeuecqlug8j/5Ajx6XmCa8ZbfdVmmPCCbLsYbwUmhKy30MV
+0HAElukw62a6d11So1MwUTAdBgNVHQ4EFgQUwsf1oUh9xpcviKVFHe9/p7NgzT4w
+HwYDVR0jBBgwFoAUwsf1oUh9xpcviKVFHe9/p7NgzT4wDwYDVR0TAQH/BAUwAwEB
+/zAKBggqhkjOPQQDAgNIADBFAiEA+4uq3IIkqj6E2XjvDh1cNqXVsJA8V9tNvpdG
+hnO5YMYCIHeKeDjhcECdfhQyP/N7CnaU+JFGqq23ornz2I5avfxT
+-----END CERTIFICATE-----
+`
+
+func TestParseCertificate_PEMAndDER(t *testing.T) {
+	cert, err := cmd.ParseCertificate([]byte(testCAPEM))
+	if err != nil {
+		t.Fatalf("ParseCertificate(PEM) failed: %v", err)
+	}
+	if cert.Subject.CommonName != "Sim CLI Test CA" {
+		t.Errorf("unexpected subject: %s", cert.Subject)
+	}
+
+	block, _ := pem.Decode([]byte(testCAPEM))
+	der, err := cmd.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseCertificate(DER) failed: %v", err)
+	}
+	if !der.Equal(cert) {
+		t.Error("expected DER and PEM to decode to the same certificate")
+	}
+
+	if _, err := cmd.ParseCertificate([]byte("not a certificate")); !errors.Is(err, cmd.ErrInvalidCertificate) {
+		t.Errorf("expected ErrInvalidCertificate, got %v", err)
+	}
+}
+
+func TestAndroidCertFilename_MatchesOpenSSL(t *testing.T) {
+	cert, _ := cmd.ParseCertificate([]byte(testCAPEM))
+
+	if got := cmd.AndroidCertFilename(cert); got != "e55f8d1f.0" {
+		t.Errorf("expected e55f8d1f.0, got %s", got)
+	}
+}
+
+// androidCertExecutor records Run calls; uid is what 'id -u' reports after 'adb root'.
+func androidCertExecutor(recorded *[]string, uid string) *recordingExecutor {
+	exec := androidRunExecutor(recorded)
+	base := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		if strings.Join(args, " ") == "-s emulator-5554 shell id -u" {
+			return []byte(uid + "\n"), nil
+		}
+
+		return base(name, args)
+	}
+
+	return exec
+}
+
+func TestAddCertificate_Android_UserStore(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidCertExecutor(&recorded, "0"))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	_ = os.WriteFile(path, []byte(testCAPEM), 0o644)
+
+	if err := cmd.AddCertificate("", path, false); err != nil {
+		t.Fatalf("AddCertificate failed: %v", err)
+	}
+
+	const remote = "/data/misc/user/0/cacerts-added/e55f8d1f.0"
+	got := strings.Join(recorded, "\n")
+	for _, want := range []string{
+		"-s emulator-5554 root",
+		"-s emulator-5554 shell mkdir -p /data/misc/user/0/cacerts-added",
+		remote,
+		"-s emulator-5554 shell chmod 644 " + remote,
+		"-s emulator-5554 shell chown system:system " + remote,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in commands:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "remount") {
+		t.Error("user store install should not remount /system")
+	}
+}
+
+func TestAddCertificate_Android_NoRootFallsBackToDownload(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidCertExecutor(&recorded, "2000"))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	_ = os.WriteFile(path, []byte(testCAPEM), 0o644)
+
+	if err := cmd.AddCertificate("", path, false); err != nil {
+		t.Fatalf("AddCertificate failed: %v", err)
+	}
+
+	last := recorded[len(recorded)-1]
+	if !strings.HasPrefix(last, "-s emulator-5554 push ") || !strings.HasSuffix(last, " /sdcard/Download/e55f8d1f.crt") {
+		t.Errorf("expected push to Download, got %q", last)
+	}
+
+	if err := cmd.AddCertificate("", path, true); !errors.Is(err, cmd.ErrCertStoreUnavailable) {
+		t.Errorf("expected ErrCertStoreUnavailable for --system without root, got %v", err)
+	}
+}
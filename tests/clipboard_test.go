@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestPushClipboard_Android_CmdClipboard(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.PushClipboard("", "it's 123 456"); err != nil {
+		t.Fatalf("PushClipboard failed: %v", err)
+	}
+
+	want := `-s emulator-5554 shell cmd clipboard set-primary-clip 'it'\''s 123 456'`
+	if len(recorded) != 1 || recorded[0] != want {
+		t.Errorf("expected %q, got %v", want, recorded)
+	}
+}
+
+func TestPushClipboard_Android_FallsBackToInputText(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	exec := androidRunExecutor(&recorded)
+	exec.onRun = func(name string, args []string) error {
+		joined := strings.Join(args, " ")
+		recorded = append(recorded, joined)
+		if strings.Contains(joined, "cmd clipboard") {
+			return errors.New("exit status 255\nOutput: No shell command implementation.")
+		}
+
+		return nil
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.PushClipboard("", "otp 123456"); err != nil {
+		t.Fatalf("PushClipboard failed: %v", err)
+	}
+
+	want := "-s emulator-5554 shell input text 'otp%s123456'"
+	if len(recorded) != 2 || recorded[1] != want {
+		t.Errorf("expected fallback %q, got %v", want, recorded)
+	}
+}
+
+func TestPushClipboard_Android_ReturnsOtherFailures(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	exec := androidRunExecutor(&recorded)
+	exec.onRun = func(name string, args []string) error {
+		joined := strings.Join(args, " ")
+		recorded = append(recorded, joined)
+		if strings.Contains(joined, "cmd clipboard") {
+			return errors.New("exit status 1\nOutput: java.lang.SecurityException: not allowed")
+		}
+
+		return nil
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	err := cmd.PushClipboard("", "otp 123456")
+	if err == nil || errors.Is(err, cmd.ErrUnsupportedPlatform) || !strings.Contains(err.Error(), "SecurityException") {
+		t.Errorf("expected the clipboard failure to be returned, got %v", err)
+	}
+	if len(recorded) != 1 {
+		t.Errorf("expected no text to be typed, got %v", recorded)
+	}
+}
+
+func TestPullClipboard_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	exec := androidRunExecutor(&recorded)
+	base := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		if strings.HasSuffix(strings.Join(args, " "), "cmd clipboard get-primary-clip") {
+			return []byte("hunter2\n"), nil
+		}
+
+		return base(name, args)
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	text, err := cmd.PullClipboard("")
+	if err != nil || text != "hunter2" {
+		t.Errorf("expected hunter2, got %q, %v", text, err)
+	}
+}
+
+func TestPullClipboard_Android_Unsupported(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	exec := androidRunExecutor(&recorded)
+	base := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		if strings.Contains(strings.Join(args, " "), "cmd clipboard") {
+			return []byte("No shell command implementation.\n"), errors.New("exit status 255")
+		}
+
+		return base(name, args)
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if _, err := cmd.PullClipboard(""); !errors.Is(err, cmd.ErrUnsupportedPlatform) {
+		t.Errorf("expected ErrUnsupportedPlatform, got %v", err)
+	}
+}
+
+func TestWatchClipboard_Android_Unsupported(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	exec := androidRunExecutor(&recorded)
+	base := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		if strings.Contains(strings.Join(args, " "), "cmd clipboard") {
+			return []byte("No shell command implementation.\n"), errors.New("exit status 255")
+		}
+
+		return base(name, args)
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.WatchClipboard(context.Background(), ""); !errors.Is(err, cmd.ErrUnsupportedPlatform) {
+		t.Errorf("expected ErrUnsupportedPlatform, got %v", err)
+	}
+	for _, call := range recorded {
+		if strings.Contains(call, "input text") {
+			t.Errorf("watch mode must not type the host clipboard: %s", call)
+		}
+	}
+}
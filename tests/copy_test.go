@@ -1,8 +1,13 @@
 package tests
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -70,6 +75,48 @@ func TestCopyCommand_To_Android(t *testing.T) {
 	}
 }
 
+func TestCopyCommand_To_DestinationExistsOnHost(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "test.txt")
+	otherPath := filepath.Join(dir, "other.txt")
+	_ = os.WriteFile(filePath, []byte("hello"), 0o644)
+	_ = os.WriteFile(otherPath, []byte("world"), 0o644)
+
+	// The destination also exists on the host, but the last argument is still the
+	// device destination rather than another source.
+	dest := t.TempDir() + "/"
+
+	var pushed []string
+	cmd.SetExecutor(androidRunExecutor(&pushed))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	root := cmd.GetRootCmd()
+	t.Cleanup(func() {
+		// Flag values persist on the shared command tree between Execute calls.
+		copyToCmd, _, _ := root.Find([]string{"copy", "to"})
+		_ = copyToCmd.Flags().Set("dest", "")
+	})
+
+	root.SetArgs([]string{"copy", "to", "emulator-5554", filePath, dest})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("copy to failed: %v", err)
+	}
+	if want := "-s emulator-5554 push " + filePath + " " + dest; strings.Join(pushed, "\n") != want {
+		t.Errorf("unexpected pushes:\n%s\nwant:\n%s", strings.Join(pushed, "\n"), want)
+	}
+
+	pushed = nil
+	root.SetArgs([]string{"copy", "to", filePath, otherPath, "--dest", "/sdcard/fixtures/"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("copy to --dest failed: %v", err)
+	}
+	if len(pushed) != 2 || !strings.HasSuffix(pushed[0], " /sdcard/fixtures/") {
+		t.Errorf("expected both paths pushed to --dest, got %v", pushed)
+	}
+}
+
 func TestCopyCommand_From_Android(t *testing.T) {
 	_ = NewTestHelpers(t)
 
@@ -124,3 +171,282 @@ func TestCopyCommand_From_Android(t *testing.T) {
 		t.Errorf("adb pull was not called")
 	}
 }
+
+func TestParseAppPath(t *testing.T) {
+	cases := map[string]cmd.AppPath{
+		"app:com.example.app/Documents/db.sqlite":       {BundleID: "com.example.app", Container: "data", Path: "Documents/db.sqlite"},
+		"app:com.example.app:app/Info.plist":            {BundleID: "com.example.app", Container: "app", Path: "Info.plist"},
+		"app:com.example.app:group.com.example/Shared/": {BundleID: "com.example.app", Container: "group.com.example", Path: "Shared/"},
+		"app:com.example.app/../../etc/passwd":          {BundleID: "com.example.app", Container: "data", Path: "etc/passwd"},
+		"app:com.example.app":                           {BundleID: "com.example.app", Container: "data", Path: ""},
+	}
+	for input, want := range cases {
+		got, err := cmd.ParseAppPath(input)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: expected %+v, got %+v", input, want, got)
+		}
+	}
+
+	for _, input := range []string{"/sdcard/file", "app:/Documents/x", "app:com.example.app:cache/x", "app:com example/x"} {
+		if _, err := cmd.ParseAppPath(input); !errors.Is(err, cmd.ErrInvalidAppPath) {
+			t.Errorf("%s: expected ErrInvalidAppPath, got %v", input, err)
+		}
+	}
+}
+
+func TestResolveAppPath(t *testing.T) {
+	container := t.TempDir()
+
+	var requested []string
+	cmd.SetExecutor(&recordingExecutor{
+		onOutput: func(name string, args []string) ([]byte, error) {
+			requested = append(requested, strings.Join(args, " "))
+			return []byte(container + "\n"), nil
+		},
+	})
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	p, _ := cmd.ParseAppPath("app:com.example.app:group.com.example/Library/")
+	got, err := cmd.ResolveAppPath("TEST-UDID", p)
+	if err != nil {
+		t.Fatalf("ResolveAppPath failed: %v", err)
+	}
+	if want := filepath.Join(container, "Library") + string(filepath.Separator); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if len(requested) != 1 || requested[0] != "simctl get_app_container TEST-UDID com.example.app group.com.example" {
+		t.Errorf("unexpected calls: %v", requested)
+	}
+}
+
+func TestCopyCommand_iOSAppContainer(t *testing.T) {
+	if runtime.GOOS != "darwin" {
+		t.Skip("iOS simulators are only supported on macOS")
+	}
+
+	_ = NewTestHelpers(t)
+
+	container := t.TempDir()
+	cmd.SetExecutor(&recordingExecutor{
+		onOutput: func(name string, args []string) ([]byte, error) {
+			switch {
+			case name == "xcrun" && len(args) >= 2 && args[1] == "list":
+				return iosSimulatorJSON("iPhone 15", "TEST-UDID", "Booted"), nil
+			case name == "xcrun" && len(args) >= 2 && args[1] == "get_app_container":
+				return []byte(container), nil
+			}
+
+			return []byte{}, nil
+		},
+	})
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	local := filepath.Join(t.TempDir(), "fixture.json")
+	_ = os.WriteFile(local, []byte(`{"ok":true}`), 0o644)
+
+	cmd.GetRootCmd().SetArgs([]string{"copy", "to", local, "app:com.example.app/Documents/"})
+	if err := cmd.GetRootCmd().Execute(); err != nil {
+		t.Fatalf("copy to failed: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(container, "Documents", "fixture.json")); err != nil || string(data) != `{"ok":true}` {
+		t.Fatalf("file was not copied into the container: %v", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "pulled.json")
+	cmd.GetRootCmd().SetArgs([]string{"copy", "from", "app:com.example.app/Documents/fixture.json", out})
+	if err := cmd.GetRootCmd().Execute(); err != nil {
+		t.Fatalf("copy from failed: %v", err)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Errorf("file was not copied out of the container: %v", err)
+	}
+}
+
+func TestCopyCommand_iOSPhotosMultipleFiles(t *testing.T) {
+	if runtime.GOOS != "darwin" {
+		t.Skip("iOS simulators are only supported on macOS")
+	}
+
+	_ = NewTestHelpers(t)
+
+	var added []string
+	cmd.SetExecutor(&recordingExecutor{
+		onOutput: func(name string, args []string) ([]byte, error) {
+			if name == "xcrun" && len(args) >= 2 && args[1] == "list" {
+				return iosSimulatorJSON("iPhone 15", "TEST-UDID", "Booted"), nil
+			}
+
+			return []byte{}, nil
+		},
+		onRun: func(name string, args []string) error {
+			added = append(added, strings.Join(args, " "))
+			return nil
+		},
+	})
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "a.jpg"), filepath.Join(dir, "b.jpg")
+	_ = os.WriteFile(first, []byte("a"), 0o644)
+	_ = os.WriteFile(second, []byte("b"), 0o644)
+
+	cmd.GetRootCmd().SetArgs([]string{"copy", "to", first, second})
+	if err := cmd.GetRootCmd().Execute(); err != nil {
+		t.Fatalf("copy to failed: %v", err)
+	}
+	if want := "simctl addmedia TEST-UDID " + first + " " + second; len(added) != 1 || added[0] != want {
+		t.Errorf("expected %q, got %v", want, added)
+	}
+}
+
+func TestCopyCommand_To_RelativeLastPathIsSource(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "a.jpg"), []byte("a"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "b.jpg"), []byte("b"), 0o644)
+	t.Chdir(dir)
+
+	var pushed []string
+	cmd.SetExecutor(androidRunExecutor(&pushed))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	cmd.GetRootCmd().SetArgs([]string{"copy", "to", "emulator-5554", "a.jpg", "b.jpg"})
+	if err := cmd.GetRootCmd().Execute(); err != nil {
+		t.Fatalf("copy to failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 push a.jpg /sdcard/Download/",
+		"-s emulator-5554 push b.jpg /sdcard/Download/",
+	}
+	if strings.Join(pushed, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected both files pushed to the default folder, got %v", pushed)
+	}
+}
+
+func TestCopyTo_AndroidRecursiveVerify(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	src := t.TempDir()
+	_ = os.MkdirAll(filepath.Join(src, "assets", "img"), 0o755)
+	_ = os.WriteFile(filepath.Join(src, "assets", "a.json"), []byte("a"), 0o644)
+	_ = os.WriteFile(filepath.Join(src, "assets", "img", "b.png"), []byte("bb"), 0o644)
+	_ = os.WriteFile(filepath.Join(src, "c.json"), []byte("ccc"), 0o644)
+	_ = os.WriteFile(filepath.Join(src, "d.txt"), []byte("d"), 0o644)
+
+	sums := map[string]string{
+		"/sdcard/fixtures/assets/a.json":    sha256Hex("a"),
+		"/sdcard/fixtures/assets/img/b.png": sha256Hex("bb"),
+		"/sdcard/fixtures/c.json":           sha256Hex("ccc"),
+	}
+
+	var pushed, shell []string
+	exec := androidRunExecutor(&pushed)
+	devices := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		joined := strings.Join(args, " ")
+		if !strings.Contains(joined, " shell ") {
+			return devices(name, args)
+		}
+		shell = append(shell, joined)
+		if strings.Contains(joined, "sha256sum") {
+			var out strings.Builder
+			for p, sum := range sums {
+				out.WriteString(sum + "  " + p + "\n")
+			}
+
+			return []byte(out.String()), nil
+		}
+
+		return []byte{}, nil
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	sources := []string{filepath.Join(src, "assets"), filepath.Join(src, "*.json")}
+	if err := cmd.CopyTo("", sources, "/sdcard/fixtures/", cmd.CopyOptions{Verify: true}); err != nil {
+		t.Fatalf("CopyTo failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 push " + filepath.Join(src, "assets", "a.json") + " /sdcard/fixtures/assets/",
+		"-s emulator-5554 push " + filepath.Join(src, "assets", "img", "b.png") + " /sdcard/fixtures/assets/img/",
+		"-s emulator-5554 push " + filepath.Join(src, "c.json") + " /sdcard/fixtures/",
+	}
+	if strings.Join(pushed, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected pushes:\n%s", strings.Join(pushed, "\n"))
+	}
+	if len(shell) == 0 || !strings.Contains(shell[0], "mkdir -p '/sdcard/fixtures/assets' '/sdcard/fixtures/assets/img' '/sdcard/fixtures'") {
+		t.Errorf("expected remote directories to be created first, got %v", shell)
+	}
+
+	sums["/sdcard/fixtures/c.json"] = sha256Hex("corrupt")
+	if err := cmd.CopyTo("", sources, "/sdcard/fixtures/", cmd.CopyOptions{Verify: true}); !errors.Is(err, cmd.ErrVerifyFailed) {
+		t.Errorf("expected ErrVerifyFailed for a corrupted copy, got %v", err)
+	}
+}
+
+func TestCopyFrom_AndroidDirectory(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	remote := map[string]string{
+		"/sdcard/DCIM/Camera/IMG_1.jpg":  "one",
+		"/sdcard/DCIM/Camera/IMG_2.jpg":  "two",
+		"/sdcard/DCIM/Screenshots/s.png": "three",
+	}
+
+	exec := androidRunExecutor(nil)
+	devices := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		joined := strings.Join(args, " ")
+		switch {
+		case strings.Contains(joined, "shell find '/sdcard/DCIM'"):
+			var out strings.Builder
+			for p, data := range remote {
+				out.WriteString(strconv.Itoa(len(data)) + " " + p + "\n")
+			}
+
+			return []byte(out.String()), nil
+		case strings.Contains(joined, "shell sha256sum"):
+			var out strings.Builder
+			for p, data := range remote {
+				out.WriteString(sha256Hex(data) + "  " + p + "\n")
+			}
+
+			return []byte(out.String()), nil
+		}
+
+		return devices(name, args)
+	}
+	exec.onRun = func(name string, args []string) error {
+		// Simulate adb pull <src> <dst>.
+		if len(args) == 5 && args[2] == "pull" {
+			return os.WriteFile(args[4], []byte(remote[args[3]]), 0o644)
+		}
+
+		return nil
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	dest := t.TempDir()
+	if err := cmd.CopyFrom("", []string{"/sdcard/DCIM"}, dest, cmd.CopyOptions{Verify: true}); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+
+	for _, rel := range []string{"DCIM/Camera/IMG_1.jpg", "DCIM/Camera/IMG_2.jpg", "DCIM/Screenshots/s.png"} {
+		if _, err := os.Stat(filepath.Join(dest, filepath.FromSlash(rel))); err != nil {
+			t.Errorf("expected %s to be pulled: %v", rel, err)
+		}
+	}
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestExpandDeeplink(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	if err := cmd.SaveDeeplink("checkout", "myapp://checkout/{store}?cart={cart}&ref={store}"); err != nil {
+		t.Fatalf("SaveDeeplink failed: %v", err)
+	}
+
+	got, err := cmd.ExpandDeeplink("@checkout", []string{"cart=42", "store=New York&Co"})
+	if err != nil {
+		t.Fatalf("ExpandDeeplink failed: %v", err)
+	}
+	if want := "myapp://checkout/New%20York%26Co?cart=42&ref=New%20York%26Co"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if names := cmd.DeeplinkNames(); len(names) != 1 || names[0] != "checkout" {
+		t.Errorf("unexpected saved names: %v", names)
+	}
+
+	failures := []struct {
+		target string
+		params []string
+	}{
+		{"@checkout", []string{"cart=42"}},                       // missing store
+		{"@missing", nil},                                        // not saved
+		{"@checkout", []string{"cart=1", "store=x", "coupon=y"}}, // unknown parameter
+		{"myapp://plain/{id}", []string{"id"}},                   // not key=value
+	}
+	for _, f := range failures {
+		if _, err := cmd.ExpandDeeplink(f.target, f.params); !errors.Is(err, cmd.ErrInvalidDeeplink) {
+			t.Errorf("%s %v: expected ErrInvalidDeeplink, got %v", f.target, f.params, err)
+		}
+	}
+
+	if err := cmd.SaveDeeplink("bad name", "myapp://x"); !errors.Is(err, cmd.ErrInvalidDeeplink) {
+		t.Errorf("expected ErrInvalidDeeplink for a name with a space, got %v", err)
+	}
+
+	if err := cmd.DeleteDeeplink("@checkout"); err != nil {
+		t.Fatalf("DeleteDeeplink failed: %v", err)
+	}
+	if len(cmd.Deeplinks()) != 0 {
+		t.Errorf("expected no deeplinks after delete, got %v", cmd.Deeplinks())
+	}
+}
+
+func TestOpenCommand_SavedDeeplink_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	if err := cmd.SaveDeeplink("order", "myapp://orders/{id}"); err != nil {
+		t.Fatalf("SaveDeeplink failed: %v", err)
+	}
+
+	var opened []string
+	exec := androidRunExecutor(nil)
+	devices := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		if strings.Contains(strings.Join(args, " "), "am start") {
+			opened = append(opened, strings.Join(args, " "))
+		}
+
+		return devices(name, args)
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	cmd.GetRootCmd().SetArgs([]string{"open", "Pixel_7", "@order", "id=17"})
+	if err := cmd.GetRootCmd().Execute(); err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+
+	want := "-s emulator-5554 shell am start -a 'android.intent.action.VIEW' -d 'myapp://orders/17'"
+	if len(opened) != 1 || opened[0] != want {
+		t.Errorf("expected %q, got %v", want, opened)
+	}
+}
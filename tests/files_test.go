@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestParseLsOutput(t *testing.T) {
+	output := "total 40\n" +
+		"drwxrws--x  5 media_rw media_rw 3452 2024-05-01 10:12 .\n" +
+		"drwx--x--x  4 root     root     4096 2024-05-01 10:12 ..\n" +
+		"drwxrws---  2 u0_a123  media_rw 3452 2024-05-02 09:30 Download\n" +
+		"-rw-rw----  1 u0_a123  media_rw 12345 2024-05-03 18:01 my  notes.txt\r\n" +
+		"lrwxrwxrwx  1 root     root       21 2024-05-01 10:12 sdcard -> /storage/self/primary\n" +
+		"crw-rw-rw-  1 root     root     1,   3 2024-05-01 10:12 null\n" +
+		"ls: ./secret: Permission denied\n"
+
+	want := []cmd.RemoteFile{
+		{Name: "Download", Size: 3452, Mode: "drwxrws---", Modified: "2024-05-02 09:30", IsDir: true},
+		{Name: "my  notes.txt", Size: 12345, Mode: "-rw-rw----", Modified: "2024-05-03 18:01"},
+		{Name: "sdcard", Size: 21, Mode: "lrwxrwxrwx", Modified: "2024-05-01 10:12", Link: "/storage/self/primary"},
+		{Name: "null", Mode: "crw-rw-rw-", Modified: "2024-05-01 10:12"},
+	}
+
+	if got := cmd.ParseLsOutput(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLsOutput() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+var (
+	testBezel = color.RGBA{R: 10, G: 10, B: 10, A: 255}
+	testShot  = color.RGBA{R: 255, A: 255}
+)
+
+// testFrame returns an opaque frame of size w x h with a transparent screen at hole.
+func testFrame(w, h int, hole image.Rectangle) *image.RGBA {
+	frame := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(frame, frame.Bounds(), image.NewUniform(testBezel), image.Point{}, draw.Src)
+	draw.Draw(frame, hole, image.Transparent, image.Point{}, draw.Src)
+
+	return frame
+}
+
+func solidImage(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+
+	return img
+}
+
+func sameColor(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
+
+func TestFindFrameScreen(t *testing.T) {
+	hole := image.Rect(10, 20, 90, 180)
+	screen, err := cmd.FindFrameScreen(testFrame(100, 200, hole))
+	if err != nil {
+		t.Fatalf("FindFrameScreen failed: %v", err)
+	}
+	if screen != hole {
+		t.Errorf("screen = %v, want %v", screen, hole)
+	}
+
+	if _, err := cmd.FindFrameScreen(testFrame(100, 200, image.Rect(10, 20, 100, 180))); err == nil {
+		t.Error("expected an error for a screen area that reaches the edge")
+	}
+	if _, err := cmd.FindFrameScreen(testFrame(100, 200, image.Rect(0, 0, 5, 5))); err == nil {
+		t.Error("expected an error for an opaque centre")
+	}
+}
+
+func TestComposeFrame(t *testing.T) {
+	frame := testFrame(100, 200, image.Rect(10, 20, 90, 180))
+	opts := cmd.FrameOptions{Background: color.White, Padding: 5}
+
+	img, err := cmd.ComposeFrame(solidImage(40, 80, testShot), frame, opts)
+	if err != nil {
+		t.Fatalf("ComposeFrame failed: %v", err)
+	}
+
+	if got := img.Bounds().Size(); got != image.Pt(110, 210) {
+		t.Fatalf("size = %v, want 110x210", got)
+	}
+	checks := []struct {
+		x, y int
+		want color.Color
+	}{
+		{0, 0, color.White},          // Padding.
+		{7, 7, testBezel},            // Bezel.
+		{55, 105, testShot},          // Centre of the screen.
+		{5 + 11, 5 + 21, testShot},   // Screen corner.
+		{5 + 95, 5 + 190, testBezel}, // Bezel below the screen.
+	}
+	for _, c := range checks {
+		if got := img.At(c.x, c.y); !sameColor(got, c.want) {
+			t.Errorf("pixel (%d,%d) = %v, want %v", c.x, c.y, got, c.want)
+		}
+	}
+
+	if _, err := cmd.ComposeFrame(solidImage(80, 40, testShot), frame, opts); err == nil {
+		t.Error("expected an error for a landscape screenshot in a portrait frame")
+	}
+}
+
+func TestComposeGenericFrame(t *testing.T) {
+	img := cmd.ComposeGenericFrame(solidImage(200, 400, testShot), false, cmd.FrameOptions{Background: color.White, Padding: 10})
+
+	// A 9px bezel (4.5% of the short side) on each side, plus the padding.
+	if got := img.Bounds().Size(); got != image.Pt(238, 438) {
+		t.Fatalf("size = %v, want 238x438", got)
+	}
+	if got := img.At(119, 219); !sameColor(got, testShot) {
+		t.Errorf("centre = %v, want the screenshot", got)
+	}
+	if got := img.At(10, 10); !sameColor(got, color.White) {
+		t.Errorf("rounded corner = %v, want the background", got)
+	}
+	if got := img.At(14, 219); sameColor(got, testShot) || sameColor(got, color.White) {
+		t.Errorf("bezel = %v, want the bezel color", got)
+	}
+}
+
+func TestParseFrameBackground(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.Color
+		wantErr bool
+	}{
+		{"transparent", color.Transparent, false},
+		{"white", color.White, false},
+		{"#fff", color.NRGBA{R: 255, G: 255, B: 255, A: 255}, false},
+		{"#1e90ff", color.NRGBA{R: 0x1e, G: 0x90, B: 0xff, A: 255}, false},
+		{"1e90ff80", color.NRGBA{R: 0x1e, G: 0x90, B: 0xff, A: 0x80}, false},
+		{"#12345", nil, true},
+		{"blue-ish", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := cmd.ParseFrameBackground(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFrameBackground(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !sameColor(got, tt.want) {
+			t.Errorf("ParseFrameBackground(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+// androidInputExecutor reports a 1080x2400 screen with a 720x1600 override.
+func androidInputExecutor(recorded *[]string) *recordingExecutor {
+	exec := androidRunExecutor(recorded)
+	base := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		if strings.HasSuffix(strings.Join(args, " "), "shell wm size") {
+			return []byte("Physical size: 1080x2400\nOverride size: 720x1600\n"), nil
+		}
+
+		return base(name, args)
+	}
+
+	return exec
+}
+
+func TestParseScreenCoordinate(t *testing.T) {
+	cases := map[string]int{"120": 120, "50%": 540, "0%": 0, "100%": 1080, "12.5%": 135}
+	for in, want := range cases {
+		got, err := cmd.ParseScreenCoordinate(in, 1080)
+		if err != nil || got != want {
+			t.Errorf("ParseScreenCoordinate(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"-5", "abc", "150%", "%"} {
+		if _, err := cmd.ParseScreenCoordinate(in, 1080); !errors.Is(err, cmd.ErrInvalidInputEvent) {
+			t.Errorf("ParseScreenCoordinate(%q) expected ErrInvalidInputEvent, got %v", in, err)
+		}
+	}
+}
+
+func TestInputTap_Android_Percentages(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidInputExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.InputTap("", "50%", "25%"); err != nil {
+		t.Fatalf("InputTap failed: %v", err)
+	}
+
+	want := "-s emulator-5554 shell input tap 360 400"
+	if len(recorded) != 1 || recorded[0] != want {
+		t.Errorf("expected %q, got %v", want, recorded)
+	}
+}
+
+func TestInputSwipe_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidInputExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.InputSwipe("", [4]string{"50%", "80%", "50%", "200"}, 500*time.Millisecond); err != nil {
+		t.Fatalf("InputSwipe failed: %v", err)
+	}
+
+	want := "-s emulator-5554 shell input swipe 360 1280 360 200 500"
+	if len(recorded) != 1 || recorded[0] != want {
+		t.Errorf("expected %q, got %v", want, recorded)
+	}
+}
+
+func TestInputTextAndKey_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidInputExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.InputText("", "hello world"); err != nil {
+		t.Fatalf("InputText failed: %v", err)
+	}
+	if err := cmd.InputKey("", "Home"); err != nil {
+		t.Fatalf("InputKey failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell input text 'hello%sworld'",
+		"-s emulator-5554 shell input keyevent KEYCODE_HOME",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands: %v", recorded)
+	}
+}
+
+func TestAndroidKeyCode(t *testing.T) {
+	cases := map[string]string{"back": "KEYCODE_BACK", "keycode_camera": "KEYCODE_CAMERA", "27": "27"}
+	for in, want := range cases {
+		if got, err := cmd.AndroidKeyCode(in); err != nil || got != want {
+			t.Errorf("AndroidKeyCode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := cmd.AndroidKeyCode("launch-rockets"); !errors.Is(err, cmd.ErrInvalidInputEvent) {
+		t.Errorf("expected ErrInvalidInputEvent, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestAndroidIntent_StartArgs(t *testing.T) {
+	intent := cmd.AndroidIntent{
+		Package:    "com.example.app",
+		Categories: []string{"android.intent.category.BROWSABLE"},
+		Extras:     []string{"debug:bool=true", "count:int=3", "name=O'Brien"},
+		Flags:      "NEW_TASK|clear_top",
+		Wait:       true,
+	}
+
+	args, err := intent.StartArgs("https://example.com/item?id=1&ref=2")
+	if err != nil {
+		t.Fatalf("StartArgs failed: %v", err)
+	}
+
+	want := `am start -W -a 'android.intent.action.VIEW' -d 'https://example.com/item?id=1&ref=2' ` +
+		`-c 'android.intent.category.BROWSABLE' -f 0x14000000 ` +
+		`--ez 'debug' 'true' --ei 'count' '3' --es 'name' 'O'\''Brien' -p 'com.example.app'`
+	if got := strings.Join(args, " "); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	invalid := []cmd.AndroidIntent{
+		{Extras: []string{"count:int=three"}},
+		{Extras: []string{"blob:bytes=00"}},
+		{Extras: []string{"novalue"}},
+		{Flags: "NEW_WINDOW"},
+		{Component: "com.example.app"},
+	}
+	for _, intent := range invalid {
+		if _, err := intent.StartArgs("myapp://"); !errors.Is(err, cmd.ErrInvalidIntent) {
+			t.Errorf("%+v: expected ErrInvalidIntent, got %v", intent, err)
+		}
+	}
+
+	if flags, _ := cmd.ParseIntentFlags("268435456"); flags != "0x10000000" {
+		t.Errorf("expected decimal flags to be converted to 0x10000000, got %s", flags)
+	}
+}
+
+func TestOpenCommand_AndroidIntentWait(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var started string
+	exec := androidRunExecutor(nil)
+	devices := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		joined := strings.Join(args, " ")
+		if strings.Contains(joined, "am start") {
+			started = joined
+			if strings.Contains(joined, "com.missing") {
+				return []byte("Starting: Intent { act=android.intent.action.VIEW }\nError: Activity not started, unable to resolve Intent\n"), nil
+			}
+
+			return []byte("Status: ok\nLaunchState: COLD\nActivity: com.example.app/.MainActivity\nTotalTime: 412\nWaitTime: 415\nComplete\n"), nil
+		}
+
+		return devices(name, args)
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	root := cmd.GetRootCmd()
+	t.Cleanup(func() {
+		// Flag values persist on the shared command tree between Execute calls.
+		openCmd, _, _ := root.Find([]string{"open"})
+		_ = openCmd.Flags().Set("component", "")
+		_ = openCmd.Flags().Set("wait", "false")
+	})
+	root.SetArgs([]string{"open", "myapp://home", "--component", "com.example.app/.MainActivity", "--wait"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	if !strings.Contains(started, "am start -W") || !strings.HasSuffix(started, "-n 'com.example.app/.MainActivity'") {
+		t.Errorf("unexpected am start call: %s", started)
+	}
+
+	root.SetArgs([]string{"open", "myapp://home", "--component", "com.missing/.Main", "--wait=false"})
+	if err := root.Execute(); !errors.Is(err, cmd.ErrInvalidIntent) {
+		t.Errorf("expected ErrInvalidIntent for an unresolved intent, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+const getAppLinksOutput = `com.other.app:
+    ID: 11111111-2222-3333-4444-555555555555
+    Signatures: [AA:BB]
+    Domain verification state:
+      other.com: verified
+  com.example.app:
+    ID: 01234567-89ab-cdef-0123-456789abcdef
+    Signatures: [CC:DD]
+    Domain verification state:
+      example.com: verified
+      shop.example.com: legacy_failure
+    User 0:
+      Verification link handling allowed: true
+`
+
+func TestParseAndroidAppLinks(t *testing.T) {
+	states := cmd.ParseAndroidAppLinks(getAppLinksOutput, "com.example.app")
+	if len(states) != 2 || states["example.com"] != "verified" || states["shop.example.com"] != "legacy_failure" {
+		t.Errorf("unexpected states: %v", states)
+	}
+}
+
+func TestVerifyAppLinks_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var calls []string
+	exec := androidRunExecutor(nil)
+	devices := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		joined := strings.Join(args, " ")
+		if strings.Contains(joined, "shell pm") {
+			calls = append(calls, joined)
+			if strings.Contains(joined, "get-app-links") {
+				return []byte(getAppLinksOutput), nil
+			}
+
+			return []byte{}, nil
+		}
+
+		return devices(name, args)
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.VerifyAppLinks("", "com.example.app", "https://example.com/items/1", ""); err != nil {
+		t.Fatalf("VerifyAppLinks failed: %v", err)
+	}
+	if len(calls) != 2 || !strings.HasSuffix(calls[0], "pm verify-app-links --re-verify com.example.app") {
+		t.Errorf("unexpected pm calls: %v", calls)
+	}
+
+	err := cmd.VerifyAppLinks("", "com.example.app", "shop.example.com", "")
+	if !errors.Is(err, cmd.ErrLinkNotVerified) || !strings.Contains(err.Error(), "legacy_failure") {
+		t.Errorf("expected legacy_failure ErrLinkNotVerified, got %v", err)
+	}
+	if err := cmd.VerifyAppLinks("", "com.example.app", "other.com", ""); !errors.Is(err, cmd.ErrLinkNotVerified) {
+		t.Errorf("expected ErrLinkNotVerified for an undeclared domain, got %v", err)
+	}
+}
+
+func TestParseEntitlements(t *testing.T) {
+	out := []byte(`Executable=/path/Example.app/Example
+<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0"><dict>
+<key>application-identifier</key><string>ABCDE12345.com.example.app</string>
+<key>com.apple.developer.associated-domains</key>
+<array><string>applinks:example.com</string><string>applinks:*.example.org?mode=developer</string><string>webcredentials:example.com</string></array>
+<key>get-task-allow</key><true/>
+</dict></plist>`)
+
+	entitlements, err := cmd.ParseEntitlements(out)
+	if err != nil {
+		t.Fatalf("ParseEntitlements failed: %v", err)
+	}
+	if got := entitlements["application-identifier"]; len(got) != 1 || got[0] != "ABCDE12345.com.example.app" {
+		t.Errorf("unexpected application-identifier: %v", got)
+	}
+
+	domains := entitlements["com.apple.developer.associated-domains"]
+	if len(domains) != 3 {
+		t.Fatalf("expected 3 associated domains, got %v", domains)
+	}
+
+	matches := map[string]bool{"example.com": true, "shop.example.org": true, "example.org": true, "example.net": false}
+	for domain, want := range matches {
+		got := false
+		for _, entry := range domains {
+			got = got || cmd.AssociatedDomainMatches(entry, domain)
+		}
+		if got != want {
+			t.Errorf("%s: expected match %v, got %v", domain, want, got)
+		}
+	}
+	if cmd.AssociatedDomainMatches("webcredentials:example.com", "example.com") {
+		t.Error("webcredentials entries should not match universal links")
+	}
+}
+
+func TestCheckAASA(t *testing.T) {
+	modern := []byte(`{"applinks":{"details":[{"appIDs":["ABCDE12345.com.example.app"],"components":[{"/":"/items/*"}]}]}}`)
+	legacy := []byte(`{"applinks":{"apps":[],"details":[{"appID":"ABCDE12345.com.example.app","paths":["*"]}]}}`)
+
+	for _, content := range [][]byte{modern, legacy} {
+		if err := cmd.CheckAASA(content, "ABCDE12345.com.example.app"); err != nil {
+			t.Errorf("CheckAASA failed: %v", err)
+		}
+		if err := cmd.CheckAASA(content, "com.example.app"); err != nil {
+			t.Errorf("CheckAASA without team ID failed: %v", err)
+		}
+		if err := cmd.CheckAASA(content, "ZZZZZ99999.com.example.app"); !errors.Is(err, cmd.ErrLinkNotVerified) {
+			t.Errorf("expected ErrLinkNotVerified for another team, got %v", err)
+		}
+	}
+
+	if err := cmd.CheckAASA([]byte(`{"webcredentials":{}}`), "com.example.app"); !errors.Is(err, cmd.ErrLinkNotVerified) {
+		t.Errorf("expected ErrLinkNotVerified without applinks, got %v", err)
+	}
+}
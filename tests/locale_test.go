@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+// androidLocaleExecutor records Run calls and reports the given persisted locale and timezone.
+func androidLocaleExecutor(recorded *[]string, locale, timezone string) *recordingExecutor {
+	exec := androidRunExecutor(recorded)
+	base := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		switch strings.Join(args, " ") {
+		case "-s emulator-5554 shell id -u":
+			return []byte("0\n"), nil
+		case "-s emulator-5554 shell getprop sys.boot_completed":
+			return []byte("1\n"), nil
+		case "-s emulator-5554 shell getprop persist.sys.locale":
+			return []byte(locale + "\n"), nil
+		case "-s emulator-5554 shell getprop persist.sys.timezone":
+			return []byte(timezone + "\n"), nil
+		}
+
+		return base(name, args)
+	}
+
+	return exec
+}
+
+func TestSetLocale_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidLocaleExecutor(&recorded, "fr-FR", "Europe/Paris"))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	err := cmd.SetLocale("", cmd.LocaleSettings{Locale: "fr_FR", Timezone: "Europe/Paris"})
+	if err != nil {
+		t.Fatalf("SetLocale failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 root",
+		"-s emulator-5554 wait-for-device",
+		"-s emulator-5554 shell setprop persist.sys.locale fr-FR",
+		"-s emulator-5554 shell service call alarm 3 s16 Europe/Paris",
+		"-s emulator-5554 reboot",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands:\n%s", strings.Join(recorded, "\n"))
+	}
+}
+
+func TestSetLocale_Android_NotApplied(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidLocaleExecutor(&recorded, "en-US", "America/New_York"))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	err := cmd.SetLocale("", cmd.LocaleSettings{Locale: "fr_FR", Timezone: "Europe/Paris"})
+	if !errors.Is(err, cmd.ErrLocaleNotApplied) {
+		t.Fatalf("expected ErrLocaleNotApplied, got %v", err)
+	}
+}
+
+func TestLocaleSettings_Normalize(t *testing.T) {
+	s, err := cmd.LocaleSettings{Locale: "pt-BR"}.Normalize()
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if s.Locale != "pt_BR" || s.Language != "pt" {
+		t.Errorf("unexpected settings: %+v", s)
+	}
+
+	s, _ = cmd.LocaleSettings{Locale: "en_GB", Language: "fr"}.Normalize()
+	if tag := s.AndroidLocaleTag(); tag != "fr-GB" {
+		t.Errorf("expected Android tag fr-GB, got %s", tag)
+	}
+
+	invalid := []cmd.LocaleSettings{
+		{Locale: "french"},
+		{Locale: "fr_FR", Language: "fr FR"},
+		{Locale: "fr_FR", Timezone: "Mars/Olympus"},
+		{Locale: "fr_FR", Timezone: "Local"},
+	}
+	for _, in := range invalid {
+		if _, err := in.Normalize(); !errors.Is(err, cmd.ErrInvalidLocale) {
+			t.Errorf("expected ErrInvalidLocale for %+v, got %v", in, err)
+		}
+	}
+}
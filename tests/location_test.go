@@ -0,0 +1,242 @@
+package tests
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+const sampleGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk>
+    <name>Loop</name>
+    <trkseg>
+      <trkpt lat="37.3349" lon="-122.0090"><ele>20</ele><time>2024-01-01T10:00:00Z</time></trkpt>
+      <trkpt lat="37.3359" lon="-122.0090"><time>2024-01-01T10:00:10Z</time></trkpt>
+      <trkpt lat="37.3369" lon="-122.0090"><time>2024-01-01T10:00:30Z</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+const sampleKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">
+  <Document>
+    <Placemark>
+      <LineString>
+        <coordinates>
+          -122.0090,37.3349,0 -122.0090,37.3359,0
+        </coordinates>
+      </LineString>
+    </Placemark>
+    <Placemark>
+      <gx:Track>
+        <when>2024-01-01T10:00:00Z</when>
+        <when>2024-01-01T10:00:05Z</when>
+        <gx:coord>-122.0080 37.3349 0</gx:coord>
+        <gx:coord>-122.0070 37.3349 0</gx:coord>
+      </gx:Track>
+    </Placemark>
+  </Document>
+</kml>`
+
+func TestParseGPX_TrackPoints(t *testing.T) {
+	points, err := cmd.ParseGPX(strings.NewReader(sampleGPX))
+	if err != nil {
+		t.Fatalf("ParseGPX failed: %v", err)
+	}
+
+	if len(points) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(points))
+	}
+	if points[0].Lat != 37.3349 || points[0].Lon != -122.0090 {
+		t.Errorf("unexpected first point: %+v", points[0])
+	}
+	if want := time.Date(2024, 1, 1, 10, 0, 30, 0, time.UTC); !points[2].Time.Equal(want) {
+		t.Errorf("expected last point time %v, got %v", want, points[2].Time)
+	}
+}
+
+func TestParseGPX_MissingCoordinates(t *testing.T) {
+	_, err := cmd.ParseGPX(strings.NewReader(`<gpx><wpt lat="10"></wpt></gpx>`))
+	if !errors.Is(err, cmd.ErrInvalidRoute) {
+		t.Fatalf("expected ErrInvalidRoute, got %v", err)
+	}
+}
+
+func TestParseKML_CoordinatesAndTrack(t *testing.T) {
+	points, err := cmd.ParseKML(strings.NewReader(sampleKML))
+	if err != nil {
+		t.Fatalf("ParseKML failed: %v", err)
+	}
+
+	if len(points) != 4 {
+		t.Fatalf("expected 4 points, got %d", len(points))
+	}
+	// KML stores longitude first.
+	if points[0].Lat != 37.3349 || points[0].Lon != -122.0090 {
+		t.Errorf("unexpected first point: %+v", points[0])
+	}
+	if points[3].Lon != -122.0070 || points[3].Time.IsZero() {
+		t.Errorf("expected timed gx:coord point, got %+v", points[3])
+	}
+}
+
+func TestParseRouteFile_UnsupportedExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "route.txt")
+	_ = os.WriteFile(path, []byte("37,-122"), 0o644)
+
+	_, err := cmd.ParseRouteFile(path)
+	if !errors.Is(err, cmd.ErrInvalidRoute) {
+		t.Fatalf("expected ErrInvalidRoute, got %v", err)
+	}
+}
+
+func TestDistanceMeters(t *testing.T) {
+	// One thousandth of a degree of latitude is roughly 111 meters.
+	d := cmd.DistanceMeters(cmd.Waypoint{Lat: 37.3349, Lon: -122.0090}, cmd.Waypoint{Lat: 37.3359, Lon: -122.0090})
+	if math.Abs(d-111.2) > 0.5 {
+		t.Errorf("expected ~111.2m, got %.2f", d)
+	}
+}
+
+func TestInterpolateRoute_UsesTimestamps(t *testing.T) {
+	points, _ := cmd.ParseGPX(strings.NewReader(sampleGPX))
+
+	samples := cmd.InterpolateRoute(points, 20, 2, time.Second)
+
+	// 30 recorded seconds at 2x take 15 seconds: samples at 0..14s plus the final point.
+	if len(samples) != 16 {
+		t.Fatalf("expected 16 samples, got %d", len(samples))
+	}
+
+	last := samples[len(samples)-1]
+	if last.Offset != 15*time.Second || last.Lat != 37.3369 {
+		t.Errorf("unexpected final sample: %+v", last)
+	}
+
+	// The first segment (10s recorded, 5s played) ends at the second waypoint.
+	if math.Abs(samples[5].Lat-37.3359) > 1e-9 {
+		t.Errorf("expected sample at 5s to reach the second waypoint, got %+v", samples[5])
+	}
+	// Halfway through the first segment.
+	if math.Abs(samples[2].Lat-(37.3349+0.0004)) > 1e-9 {
+		t.Errorf("unexpected interpolated latitude at 2s: %v", samples[2].Lat)
+	}
+}
+
+func TestInterpolateRoute_UsesBaseSpeedWithoutTimestamps(t *testing.T) {
+	a := cmd.Waypoint{Lat: 37.3349, Lon: -122.0090}
+	b := cmd.Waypoint{Lat: 37.3359, Lon: -122.0090}
+	dist := cmd.DistanceMeters(a, b)
+
+	samples := cmd.InterpolateRoute([]cmd.Waypoint{a, b}, dist/10, 1, time.Second)
+
+	if got := samples[len(samples)-1].Offset.Round(time.Millisecond); got != 10*time.Second {
+		t.Errorf("expected route to take 10s, got %v", got)
+	}
+	if math.Abs(cmd.RouteAverageSpeed([]cmd.Waypoint{a, b}, dist/10, 1)-dist/10) > 1e-6 {
+		t.Error("expected average speed to equal base speed")
+	}
+}
+
+func TestInterpolateRoute_SinglePoint(t *testing.T) {
+	samples := cmd.InterpolateRoute([]cmd.Waypoint{{Lat: 1, Lon: 2}}, 20, 1, time.Second)
+	if len(samples) != 1 || samples[0].Lat != 1 || samples[0].Lon != 2 {
+		t.Errorf("expected a single sample at the waypoint, got %+v", samples)
+	}
+}
+
+func TestParseSpeedMultiplier(t *testing.T) {
+	cases := map[string]float64{"2x": 2, "0.5x": 0.5, "3": 3, "1.5X": 1.5}
+	for in, want := range cases {
+		got, err := cmd.ParseSpeedMultiplier(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSpeedMultiplier(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "fast", "0x", "-2x"} {
+		if _, err := cmd.ParseSpeedMultiplier(in); !errors.Is(err, cmd.ErrInvalidSpeed) {
+			t.Errorf("ParseSpeedMultiplier(%q) expected ErrInvalidSpeed, got %v", in, err)
+		}
+	}
+}
+
+// androidRunExecutor reports a single running emulator and records the args of every Run call.
+func androidRunExecutor(recorded *[]string) *recordingExecutor {
+	return &recordingExecutor{
+		onOutput: func(name string, args []string) ([]byte, error) {
+			if name == "adb" {
+				joined := strings.Join(args, " ")
+				switch {
+				case joined == "devices":
+					return []byte("List of devices attached\nemulator-5554\tdevice\n"), nil
+				case strings.Contains(joined, "avd name"):
+					return []byte("Pixel_7\nOK\n"), nil
+				}
+			}
+
+			return []byte{}, nil
+		},
+		onRun: func(name string, args []string) error {
+			*recorded = append(*recorded, strings.Join(args, " "))
+			return nil
+		},
+	}
+}
+
+func TestSetLocation_Android_LongitudeFirst(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SetLocation("", 48.8584, 2.2945); err != nil {
+		t.Fatalf("SetLocation failed: %v", err)
+	}
+
+	want := "-s emulator-5554 emu geo fix 2.2945 48.8584"
+	if len(recorded) != 1 || recorded[0] != want {
+		t.Errorf("expected %q, got %v", want, recorded)
+	}
+}
+
+func TestSetLocation_OutOfRange(t *testing.T) {
+	if err := cmd.SetLocation("", 91, 0); !errors.Is(err, cmd.ErrInvalidCoordinate) {
+		t.Fatalf("expected ErrInvalidCoordinate, got %v", err)
+	}
+}
+
+func TestPlayRoute_Android_SendsGeoFixes(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	// Two waypoints ~111m apart at 111 m/s and 10x speed finish in ~0.1s.
+	gpx := `<gpx><rte><rtept lat="37.3349" lon="-122.0090"/><rtept lat="37.3359" lon="-122.0090"/></rte></gpx>`
+	path := filepath.Join(t.TempDir(), "route.gpx")
+	_ = os.WriteFile(path, []byte(gpx), 0o644)
+
+	if err := cmd.PlayRoute("", path, 111, 10); err != nil {
+		t.Fatalf("PlayRoute failed: %v", err)
+	}
+
+	if len(recorded) != 2 {
+		t.Fatalf("expected 2 geo fixes, got %v", recorded)
+	}
+	if recorded[0] != "-s emulator-5554 emu geo fix -122.009 37.3349" {
+		t.Errorf("unexpected first geo fix: %s", recorded[0])
+	}
+	if recorded[1] != "-s emulator-5554 emu geo fix -122.009 37.3359" {
+		t.Errorf("unexpected last geo fix: %s", recorded[1])
+	}
+}
@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestSetNetworkProfile_Android_Edge(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	p, err := cmd.ResolveNetworkProfile("EDGE")
+	if err != nil {
+		t.Fatalf("ResolveNetworkProfile failed: %v", err)
+	}
+	if err := cmd.SetNetworkProfile("", "edge", p); err != nil {
+		t.Fatalf("SetNetworkProfile failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell svc wifi enable",
+		"-s emulator-5554 shell svc data enable",
+		"-s emulator-5554 emu network speed edge",
+		"-s emulator-5554 emu network delay edge",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands:\n%s", strings.Join(recorded, "\n"))
+	}
+}
+
+func TestSetNetworkProfile_Android_Offline(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SetNetworkProfile("", "offline", cmd.NetworkProfile{Offline: true}); err != nil {
+		t.Fatalf("SetNetworkProfile failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell svc wifi disable",
+		"-s emulator-5554 shell svc data disable",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands:\n%s", strings.Join(recorded, "\n"))
+	}
+}
+
+func TestNetworkProfile_Validate(t *testing.T) {
+	valid := []cmd.NetworkProfile{
+		{Speed: "lte", Latency: "none"},
+		{Speed: "128:512", Latency: "200"},
+		{Speed: "full", Latency: "100:300"},
+		{Offline: true},
+	}
+	for _, p := range valid {
+		if err := p.Validate(); err != nil {
+			t.Errorf("expected %+v to be valid, got %v", p, err)
+		}
+	}
+
+	for _, p := range []cmd.NetworkProfile{{Speed: "5g"}, {Latency: "slow"}, {Speed: "1:2:3"}} {
+		if err := p.Validate(); !errors.Is(err, cmd.ErrInvalidNetworkProfile) {
+			t.Errorf("expected ErrInvalidNetworkProfile for %+v, got %v", p, err)
+		}
+	}
+}
+
+func TestSaveNetworkProfile_OverridesBuiltin(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	if err := cmd.SaveNetworkProfile("subway", cmd.NetworkProfile{Speed: "64:128", Latency: "400:900"}); err != nil {
+		t.Fatalf("SaveNetworkProfile failed: %v", err)
+	}
+	if err := cmd.SaveNetworkProfile("3g", cmd.NetworkProfile{Speed: "hsdpa", Latency: "umts"}); err != nil {
+		t.Fatalf("SaveNetworkProfile failed: %v", err)
+	}
+
+	p, err := cmd.ResolveNetworkProfile("subway")
+	if err != nil || p.Latency != "400:900" {
+		t.Errorf("expected saved profile, got %+v, %v", p, err)
+	}
+	if p, _ := cmd.ResolveNetworkProfile("3g"); p.Speed != "hsdpa" {
+		t.Errorf("expected config profile to override built-in 3g, got %+v", p)
+	}
+	if _, err := cmd.ResolveNetworkProfile("satellite"); !errors.Is(err, cmd.ErrInvalidNetworkProfile) {
+		t.Errorf("expected ErrInvalidNetworkProfile, got %v", err)
+	}
+
+	if err := cmd.SaveNetworkProfile("Subway", cmd.NetworkProfile{Speed: "gsm"}); err != nil {
+		t.Fatalf("SaveNetworkProfile failed: %v", err)
+	}
+	if p, _ := cmd.ResolveNetworkProfile("Subway"); p.Speed != "gsm" {
+		t.Errorf("expected the exact match to win, got %+v", p)
+	}
+	if _, err := cmd.ResolveNetworkProfile("SUBWAY"); !errors.Is(err, cmd.ErrInvalidNetworkProfile) {
+		t.Errorf("expected an ambiguity error, got %v", err)
+	}
+}
+
+func TestFlakyNetwork_CyclesAndRestores(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	err := cmd.FlakyNetwork(context.Background(), "", []string{"lte", "offline"}, 20*time.Millisecond, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("FlakyNetwork failed: %v", err)
+	}
+
+	got := strings.Join(recorded, "\n")
+	if !strings.Contains(got, "emu network speed lte") || !strings.Contains(got, "svc wifi disable") {
+		t.Errorf("expected both profiles to be applied:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "emu network speed full\n-s emulator-5554 emu network delay none") {
+		t.Errorf("expected the full network to be restored last:\n%s", got)
+	}
+}
+
+func TestFlakyNetwork_RestoresAfterFailure(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	exec := androidRunExecutor(&recorded)
+	exec.onRun = func(name string, args []string) error {
+		joined := strings.Join(args, " ")
+		recorded = append(recorded, joined)
+		if strings.HasSuffix(joined, "emu network delay umts") {
+			return errors.New("console not available")
+		}
+
+		return nil
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	err := cmd.FlakyNetwork(context.Background(), "", []string{"offline", "3g"}, 20*time.Millisecond, 0)
+	if err == nil {
+		t.Fatal("expected the failed profile to stop the cycle")
+	}
+
+	got := strings.Join(recorded, "\n")
+	if !strings.HasSuffix(got, "emu network speed full\n-s emulator-5554 emu network delay none") {
+		t.Errorf("expected the full network to be restored after the failure:\n%s", got)
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestSetBattery_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SetBattery("", 15, false); err != nil {
+		t.Fatalf("SetBattery failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 emu power ac off",
+		"-s emulator-5554 emu power status discharging",
+		"-s emulator-5554 emu power capacity 15",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands: %v", recorded)
+	}
+
+	if err := cmd.SetBattery("", 101, true); !errors.Is(err, cmd.ErrInvalidSetting) {
+		t.Errorf("expected ErrInvalidSetting, got %v", err)
+	}
+}
+
+func TestSetDoze_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SetDoze("", "Enter"); err != nil {
+		t.Fatalf("SetDoze enter failed: %v", err)
+	}
+	if err := cmd.SetDoze("", "exit"); err != nil {
+		t.Fatalf("SetDoze exit failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell dumpsys battery unplug",
+		"-s emulator-5554 shell dumpsys deviceidle force-idle",
+		"-s emulator-5554 shell dumpsys deviceidle unforce",
+		"-s emulator-5554 shell dumpsys battery reset",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands: %v", recorded)
+	}
+
+	if err := cmd.SetDoze("", "nap"); !errors.Is(err, cmd.ErrInvalidSetting) {
+		t.Errorf("expected ErrInvalidSetting, got %v", err)
+	}
+}
+
+func TestResetPower_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.ResetPower(""); err != nil {
+		t.Fatalf("ResetPower failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell dumpsys deviceidle unforce",
+		"-s emulator-5554 shell dumpsys battery reset",
+		"-s emulator-5554 emu power ac on",
+		"-s emulator-5554 emu power status charging",
+		"-s emulator-5554 emu power capacity 100",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands: %v", recorded)
+	}
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+// androidPrivacyExecutor reports a single running emulator and records adb shell calls.
+// Permissions listed in notRequested fail like `pm grant` does for undeclared permissions.
+func androidPrivacyExecutor(recorded *[]string, notRequested ...string) *recordingExecutor {
+	return &recordingExecutor{
+		onOutput: func(name string, args []string) ([]byte, error) {
+			if name == "adb" {
+				joined := strings.Join(args, " ")
+				switch {
+				case joined == "devices":
+					return []byte("List of devices attached\nemulator-5554\tdevice\n"), nil
+				case strings.Contains(joined, "avd name"):
+					return []byte("Pixel_7\nOK\n"), nil
+				}
+			}
+
+			return []byte{}, nil
+		},
+		onRun: func(name string, args []string) error {
+			joined := strings.Join(args, " ")
+			*recorded = append(*recorded, joined)
+			for _, perm := range notRequested {
+				if strings.HasSuffix(joined, perm) {
+					return errors.New("exit status 255\nOutput: java.lang.SecurityException: Package has not requested permission " + perm)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func TestPrivacyGrant_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidPrivacyExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SetAppPrivacy("", "grant", "com.example.app", []string{"camera"}); err != nil {
+		t.Fatalf("SetAppPrivacy failed: %v", err)
+	}
+
+	want := "-s emulator-5554 shell pm grant com.example.app android.permission.CAMERA"
+	if len(recorded) != 1 || recorded[0] != want {
+		t.Errorf("expected %q, got %v", want, recorded)
+	}
+}
+
+func TestPrivacyGrant_Android_SkipsUnrequestedPermissions(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidPrivacyExecutor(&recorded, "android.permission.READ_MEDIA_VIDEO", "android.permission.READ_EXTERNAL_STORAGE"))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SetAppPrivacy("", "grant", "com.example.app", []string{"photos"}); err != nil {
+		t.Fatalf("expected undeclared permissions to be skipped, got %v", err)
+	}
+}
+
+func TestPrivacyGrant_Android_NothingRequested(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidPrivacyExecutor(&recorded, "android.permission.CAMERA"))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	err := cmd.SetAppPrivacy("", "grant", "com.example.app", []string{"camera"})
+	if err == nil || !strings.Contains(err.Error(), "does not request") {
+		t.Fatalf("expected not requested error, got %v", err)
+	}
+}
+
+func TestPrivacyReset_Android_ClearsFlagsAndAppOps(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidPrivacyExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SetAppPrivacy("", "reset", "com.example.app", []string{"notifications"}); err != nil {
+		t.Fatalf("SetAppPrivacy failed: %v", err)
+	}
+
+	joined := strings.Join(recorded, "\n")
+	for _, want := range []string{
+		"pm revoke com.example.app android.permission.POST_NOTIFICATIONS",
+		"pm clear-permission-flags com.example.app android.permission.POST_NOTIFICATIONS user-set user-fixed",
+		"appops set com.example.app POST_NOTIFICATION default",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected call containing %q, got:\n%s", want, joined)
+		}
+	}
+}
+
+func TestPrivacy_UnsupportedOnPlatform(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidPrivacyExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	err := cmd.SetAppPrivacy("", "grant", "com.example.app", []string{"siri"})
+	if !errors.Is(err, cmd.ErrPrivacyUnsupported) {
+		t.Fatalf("expected ErrPrivacyUnsupported, got %v", err)
+	}
+}
+
+func TestPrivacy_UnknownService(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	err := cmd.SetAppPrivacy("", "grant", "com.example.app", []string{"teleport"})
+	if !errors.Is(err, cmd.ErrUnknownPrivacyService) {
+		t.Fatalf("expected ErrUnknownPrivacyService, got %v", err)
+	}
+}
+
+func TestPrivacyProfile_Apply(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidPrivacyExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	profilePath := filepath.Join(t.TempDir(), "profile.json")
+	profile := `{"app": "com.example.app", "permissions": {"microphone": "revoke", "camera": "grant"}}`
+	if err := os.WriteFile(profilePath, []byte(profile), 0o644); err != nil {
+		t.Fatalf("failed to write profile: %v", err)
+	}
+
+	if err := cmd.ApplyPrivacyProfile("", profilePath); err != nil {
+		t.Fatalf("ApplyPrivacyProfile failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell pm grant com.example.app android.permission.CAMERA",
+		"-s emulator-5554 shell pm revoke com.example.app android.permission.RECORD_AUDIO",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected calls %v, got %v", want, recorded)
+	}
+}
+
+func TestPrivacyProfile_InvalidAction(t *testing.T) {
+	profilePath := filepath.Join(t.TempDir(), "profile.json")
+	profile := `{"app": "com.example.app", "permissions": {"camera": "allow"}}`
+	if err := os.WriteFile(profilePath, []byte(profile), 0o644); err != nil {
+		t.Fatalf("failed to write profile: %v", err)
+	}
+
+	_, err := cmd.LoadPrivacyProfile(profilePath)
+	if !errors.Is(err, cmd.ErrInvalidPrivacyAction) {
+		t.Fatalf("expected ErrInvalidPrivacyAction, got %v", err)
+	}
+}
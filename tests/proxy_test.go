@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestParseProxyAddress(t *testing.T) {
+	host, port, err := cmd.ParseProxyAddress("localhost:8888")
+	if err != nil || host != "localhost" || port != "8888" {
+		t.Fatalf("unexpected result: %q %q %v", host, port, err)
+	}
+
+	for _, addr := range []string{"localhost", ":8888", "proxy:0", "proxy:http", "proxy:70000"} {
+		if _, _, err := cmd.ParseProxyAddress(addr); !errors.Is(err, cmd.ErrInvalidProxy) {
+			t.Errorf("ParseProxyAddress(%q) expected ErrInvalidProxy, got %v", addr, err)
+		}
+	}
+}
+
+func TestAndroidProxyHost_RewritesLoopback(t *testing.T) {
+	cases := map[string]string{
+		"localhost":    "10.0.2.2",
+		"127.0.0.1":    "10.0.2.2",
+		"::1":          "10.0.2.2",
+		"192.168.1.20": "192.168.1.20",
+		"proxy.local":  "proxy.local",
+	}
+	for in, want := range cases {
+		if got := cmd.AndroidProxyHost(in); got != want {
+			t.Errorf("AndroidProxyHost(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSetProxy_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SetProxy("", "localhost:8888", false, ""); err != nil {
+		t.Fatalf("SetProxy failed: %v", err)
+	}
+	if err := cmd.ClearProxy("", false, ""); err != nil {
+		t.Fatalf("ClearProxy failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell settings put global http_proxy 10.0.2.2:8888",
+		"-s emulator-5554 shell settings put global http_proxy :0",
+	}
+	if len(recorded) != 2 || recorded[0] != want[0] || recorded[1] != want[1] {
+		t.Errorf("unexpected commands: %v", recorded)
+	}
+}
+
+func TestGetAndroidProxy(t *testing.T) {
+	cases := map[string]string{"10.0.2.2:8888\n": "10.0.2.2:8888", "null\n": "", ":0\n": ""}
+	for out, want := range cases {
+		cmd.SetExecutor(&recordingExecutor{
+			onOutput: func(name string, args []string) ([]byte, error) { return []byte(out), nil },
+		})
+		if got := cmd.GetAndroidProxy("emulator-5554"); got != want {
+			t.Errorf("GetAndroidProxy with %q = %q, want %q", out, got, want)
+		}
+	}
+	cmd.SetExecutor(&cmd.OSCommandExecutor{})
+}
+
+func TestGetHostProxy_ParsesScutil(t *testing.T) {
+	cmd.SetExecutor(&recordingExecutor{
+		onOutput: func(name string, args []string) ([]byte, error) {
+			return []byte("<dictionary> {\n  HTTPEnable : 1\n  HTTPPort : 8888\n  HTTPProxy : 127.0.0.1\n}\n"), nil
+		},
+	})
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if got := cmd.GetHostProxy(); got != "127.0.0.1:8888" {
+		t.Errorf("expected 127.0.0.1:8888, got %q", got)
+	}
+}
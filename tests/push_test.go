@@ -1,11 +1,13 @@
 package tests
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/annurdien/sim-cli/cmd"
 )
@@ -93,43 +95,227 @@ func TestPushCommand_InvalidJSON(t *testing.T) {
 }
 
 func TestPushCommand_Android(t *testing.T) {
-	if runtime.GOOS != "darwin" {
-		t.Skip("iOS push checks runtime first, skip on linux/windows")
+	_ = NewTestHelpers(t)
+
+	payloadPath := filepath.Join(t.TempDir(), "payload.json")
+	_ = os.WriteFile(payloadPath, []byte(`{"aps":{"alert":{"title":"Order","subtitle":"#42","body":"It's shipped"}},"orderId":42}`), 0o644)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SendPushNotification("", "com.example.app", payloadPath); err != nil {
+		t.Fatalf("SendPushNotification failed: %v", err)
+	}
+
+	want := `-s emulator-5554 shell cmd notification post -t 'Order - #42' 'sim-push' 'It'\''s shipped'`
+	if len(recorded) != 1 || recorded[0] != want {
+		t.Errorf("expected %q, got %v", want, recorded)
 	}
+}
 
+func TestPushCommand_Android_SilentBroadcast(t *testing.T) {
 	_ = NewTestHelpers(t)
 
-	const (
-		emulatorSerial = "emulator-5554"
-		bundleID       = "com.example.app"
-	)
+	payloadPath := filepath.Join(t.TempDir(), "silent.json")
+	_ = os.WriteFile(payloadPath, []byte(`{"aps":{"content-available":1},"sync":"inbox","meta":{"v":2}}`), 0o644)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SendPushNotification("", "com.example.app", payloadPath); err != nil {
+		t.Fatalf("SendPushNotification failed: %v", err)
+	}
+
+	want := "-s emulator-5554 shell am broadcast -a com.google.android.c2dm.intent.RECEIVE -p com.example.app " +
+		`--es 'meta' '{"v":2}' --es 'sync' 'inbox'`
+	if len(recorded) != 1 || recorded[0] != want {
+		t.Errorf("expected %q, got %v", want, recorded)
+	}
+}
+
+func TestTranslateAPNsPayload(t *testing.T) {
+	p, err := cmd.TranslateAPNsPayload([]byte(`{"aps":{"alert":"Hello","badge":3,"thread-id":"chat"},"room":"general"}`))
+	if err != nil {
+		t.Fatalf("TranslateAPNsPayload failed: %v", err)
+	}
+	if p.Body != "Hello" || p.Title != "" || p.Tag != "chat" || p.Silent || p.Data["room"] != "general" {
+		t.Errorf("unexpected translation: %+v", p)
+	}
+	if p.Via(cmd.AndroidPushAuto) != cmd.AndroidPushNotification {
+		t.Errorf("expected alert push to be posted as a notification")
+	}
+
+	args := strings.Join(p.BroadcastArgs("com.example.app"), " ")
+	if !strings.Contains(args, "--es 'gcm.notification.body' 'Hello'") || !strings.Contains(args, "--es 'room' 'general'") {
+		t.Errorf("unexpected broadcast args: %s", args)
+	}
+
+	if _, err := cmd.TranslateAPNsPayload([]byte(`{"aps":{"alert":42}}`)); err == nil {
+		t.Error("expected an error for a numeric alert")
+	}
+}
+
+func TestRenderPushPayload(t *testing.T) {
+	content := []byte(`{"aps":{"alert":{"title":"{{title|Hi}}","body":"{{body}}"}}}`)
+
+	out, err := cmd.RenderPushPayload(content, map[string]string{"body": `Say "hello"`, "aps.badge": "3", "extra.id": "abc"})
+	if err != nil {
+		t.Fatalf("RenderPushPayload failed: %v", err)
+	}
+	for _, want := range []string{`"title": "Hi"`, `"body": "Say \"hello\""`, `"badge": 3`, `"id": "abc"`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected %s in rendered payload:\n%s", want, out)
+		}
+	}
+
+	if _, err := cmd.RenderPushPayload(content, nil); err == nil || !strings.Contains(err.Error(), "body") {
+		t.Errorf("expected missing body error, got %v", err)
+	}
+}
+
+func TestValidatePushPayload(t *testing.T) {
+	for _, name := range cmd.PushTemplateNames() {
+		tmpl, err := cmd.PushTemplate(name)
+		if err != nil {
+			t.Fatalf("PushTemplate(%s) failed: %v", name, err)
+		}
+		out, err := cmd.RenderPushPayload([]byte(tmpl), nil)
+		if err != nil {
+			t.Fatalf("template %s does not render: %v", name, err)
+		}
+		if err := cmd.ValidatePushPayload(out); err != nil {
+			t.Errorf("template %s is invalid: %v", name, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"unknown key":   `{"aps":{"alrt":"typo"}}`,
+		"badge type":    `{"aps":{"badge":"3"}}`,
+		"missing aps":   `{"alert":"hi"}`,
+		"alert key":     `{"aps":{"alert":{"titel":"x"}}}`,
+		"content flag":  `{"aps":{"content-available":true}}`,
+		"too large":     `{"aps":{"alert":"` + strings.Repeat("x", 4096) + `"}}`,
+		"not an object": `["aps"]`,
+	}
+	for name, payload := range invalid {
+		if err := cmd.ValidatePushPayload([]byte(payload)); !errors.Is(err, cmd.ErrInvalidPushPayload) {
+			t.Errorf("%s: expected ErrInvalidPushPayload, got %v", name, err)
+		}
+	}
+}
+
+func TestSendPush_AndroidRepeat(t *testing.T) {
+	_ = NewTestHelpers(t)
 
 	payloadPath := filepath.Join(t.TempDir(), "payload.json")
-	_ = os.WriteFile(payloadPath, []byte(`{"aps":{"alert":"test"}}`), 0o644)
+	_ = os.WriteFile(payloadPath, []byte(`{"aps":{"alert":"{{msg}}"}}`), 0o644)
 
-	adbDevicesOutput := "List of devices attached\n" + emulatorSerial + "\tdevice\n"
-	adbNameOutput := "Pixel_7\nOK\n"
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
 
-	exec := &recordingExecutor{
-		onOutput: func(name string, args []string) ([]byte, error) {
-			if name == "adb" {
-				joined := strings.Join(args, " ")
-				switch {
-				case joined == "devices":
-					return []byte(adbDevicesOutput), nil
-				case strings.Contains(joined, "avd name"):
-					return []byte(adbNameOutput), nil
-				}
-			}
+	err := cmd.SendPush("", "com.example.app", payloadPath, cmd.PushOptions{
+		Vars:       map[string]string{"msg": "ping"},
+		Repeat:     3,
+		Interval:   time.Millisecond,
+		AndroidVia: cmd.AndroidPushAuto,
+	})
+	if err != nil {
+		t.Fatalf("SendPush failed: %v", err)
+	}
 
-			return []byte{}, nil
-		},
+	want := "-s emulator-5554 shell cmd notification post 'sim-push' 'ping'"
+	if len(recorded) != 3 || recorded[0] != want || recorded[2] != want {
+		t.Errorf("expected 3 x %q, got %v", want, recorded)
 	}
-	cmd.SetExecutor(exec)
+}
+
+func TestSendPush_SimulatorTargetBundle(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "02-silent.apns"),
+		[]byte(`{"Simulator Target Bundle":"com.example.chat","aps":{"content-available":1},"room":"general"}`), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "01-alert.apns"), []byte(`{"aps":{"alert":"first"}}`), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	err := cmd.SendPush("", "", dir, cmd.PushOptions{AndroidVia: cmd.AndroidPushAuto})
+	if !errors.Is(err, cmd.ErrNoPushTarget) || !strings.Contains(err.Error(), "01-alert.apns") {
+		t.Fatalf("expected ErrNoPushTarget for 01-alert.apns, got %v", err)
+	}
+
+	if err := cmd.SaveConfig(&cmd.Config{DefaultApp: "com.example.app"}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	if err := cmd.SendPush("", "", dir, cmd.PushOptions{AndroidVia: cmd.AndroidPushAuto}); err != nil {
+		t.Fatalf("SendPush failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell cmd notification post 'sim-push' 'first'",
+		"-s emulator-5554 shell am broadcast -a com.google.android.c2dm.intent.RECEIVE -p com.example.chat --es 'room' 'general'",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %q, got %q", want, recorded)
+	}
+}
+
+func TestSendPush_DefaultDeviceNotRunning(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	payloadPath := filepath.Join(t.TempDir(), "payload.json")
+	_ = os.WriteFile(payloadPath, []byte(`{"aps":{"alert":"ping"}}`), 0o644)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.SaveConfig(&cmd.Config{DefaultDevice: "Pixel_9_Pro"}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	if err := cmd.SendPush("", "com.example.app", payloadPath, cmd.PushOptions{AndroidVia: cmd.AndroidPushAuto}); err != nil {
+		t.Fatalf("SendPush failed: %v", err)
+	}
+
+	want := "-s emulator-5554 shell cmd notification post 'sim-push' 'ping'"
+	if len(recorded) != 1 || recorded[0] != want {
+		t.Errorf("expected the first running device to get %q, got %v", want, recorded)
+	}
+}
+
+func TestPushCommand_DeviceAndPayload(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	payloadPath := filepath.Join(t.TempDir(), "payload.apns")
+	_ = os.WriteFile(payloadPath, []byte(`{"Simulator Target Bundle":"com.example.chat","aps":{"content-available":1},"room":"general"}`), 0o644)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
 	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
 
-	err := cmd.SendPushNotification(emulatorSerial, bundleID, payloadPath)
-	if err == nil || !strings.Contains(err.Error(), "not supported for Android") {
-		t.Fatalf("expected Android not supported error, got %v", err)
+	// The first of two arguments is a device when one matches, so the bundle comes from the payload.
+	cmd.GetRootCmd().SetArgs([]string{"push", "emulator-5554", payloadPath})
+	if err := cmd.GetRootCmd().Execute(); err != nil {
+		t.Fatalf("push <device> <payload> failed: %v", err)
+	}
+
+	// Otherwise it is the bundle ID.
+	cmd.GetRootCmd().SetArgs([]string{"push", "com.example.app", payloadPath})
+	if err := cmd.GetRootCmd().Execute(); err != nil {
+		t.Fatalf("push <bundle-id> <payload> failed: %v", err)
+	}
+
+	want := []string{
+		"-s emulator-5554 shell am broadcast -a com.google.android.c2dm.intent.RECEIVE -p com.example.chat --es 'room' 'general'",
+		"-s emulator-5554 shell am broadcast -a com.google.android.c2dm.intent.RECEIVE -p com.example.app --es 'room' 'general'",
+	}
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %q, got %q", want, recorded)
 	}
 }
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func writeSeedManifest(t *testing.T, dir, content string) string {
+	t.Helper()
+
+	p := filepath.Join(dir, "seed.yaml")
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestLoadSeedManifest_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":       "medias:\n  - a.jpg\n",
+		"unknown service":   "app: com.example.app\npermissions:\n  telepathy: grant\n",
+		"bad action":        "app: com.example.app\npermissions:\n  camera: allow\n",
+		"no destination":    "files:\n  - src: fixtures/\n",
+		"relative android":  "files:\n  - src: fixtures/\n    android: sdcard/fixtures\n",
+		"ios not app: path": "files:\n  - src: fixtures/\n    ios: /Documents\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := cmd.LoadSeedManifest(writeSeedManifest(t, t.TempDir(), content))
+			if !errors.Is(err, cmd.ErrInvalidSeedManifest) {
+				t.Errorf("expected ErrInvalidSeedManifest, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSeed_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	dir := t.TempDir()
+	mtime := time.Unix(1700000000, 0)
+	for rel, data := range map[string]string{
+		"photos/a.jpg":       "aaaa",
+		"photos/b.jpg":       "bb",
+		"contacts/team.vcf":  "BEGIN:VCARD\nEND:VCARD\n",
+		"fixtures/user.json": "{}",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		_ = os.MkdirAll(filepath.Dir(p), 0o755)
+		_ = os.WriteFile(p, []byte(data), 0o644)
+		_ = os.Chtimes(p, mtime, mtime)
+	}
+
+	manifest := writeSeedManifest(t, dir, `
+app: com.example.app
+media:
+  - photos/*.jpg
+contacts:
+  - contacts/team.vcf
+files:
+  - src: fixtures/
+    ios: app:com.example.app/Documents/fixtures
+    android: /sdcard/Android/data/com.example.app/files/fixtures
+deeplinks:
+  - myapp://seeded
+  - myapp://login?user=qa
+`)
+
+	var pushed, shell []string
+	exec := androidRunExecutor(&pushed)
+	devices := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		joined := strings.Join(args, " ")
+		switch {
+		case strings.Contains(joined, "shell cat /data/local/tmp/sim-cli-seed.json"):
+			return []byte(`{"seeded":["deeplink:myapp://seeded"]}`), nil
+		case strings.Contains(joined, "find '/sdcard/DCIM/Seed'"):
+			// a.jpg is already on the device.
+			return []byte("4 " + strconv.FormatInt(mtime.Unix(), 10) + " /sdcard/DCIM/Seed/a.jpg\n"), nil
+		case strings.Contains(joined, " shell "):
+			shell = append(shell, joined)
+			return []byte("Starting: Intent { }\n"), nil
+		}
+
+		return devices(name, args)
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.Seed("", manifest, cmd.SeedOptions{}); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+
+	wantPushed := []string{
+		"-s emulator-5554 push " + filepath.Join(dir, "photos", "b.jpg") + " /sdcard/DCIM/Seed/",
+		"-s emulator-5554 push " + filepath.Join(dir, "contacts", "team.vcf") + " /sdcard/Download/team.vcf",
+		"-s emulator-5554 push " + filepath.Join(dir, "fixtures", "user.json") + " /sdcard/Android/data/com.example.app/files/fixtures/",
+	}
+	if strings.Join(pushed, "\n") != strings.Join(wantPushed, "\n") {
+		t.Errorf("unexpected pushes:\n%s", strings.Join(pushed, "\n"))
+	}
+
+	all := strings.Join(shell, "\n")
+	for _, want := range []string{
+		"am broadcast -a android.intent.action.MEDIA_SCANNER_SCAN_FILE -d 'file:///sdcard/DCIM/Seed/b.jpg'",
+		"am start -a android.intent.action.VIEW -d 'file:///sdcard/Download/team.vcf' -t text/x-vcard",
+		"am start -a 'android.intent.action.VIEW' -d 'myapp://login?user=qa'",
+		"> /data/local/tmp/sim-cli-seed.json",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("expected a shell command containing %q, got:\n%s", want, all)
+		}
+	}
+	if strings.Contains(all, "MEDIA_SCANNER_SCAN_FILE -d 'file:///sdcard/DCIM/Seed/a.jpg'") {
+		t.Error("a.jpg is already on the device and should not be pushed or scanned again")
+	}
+	if strings.Contains(all, "-d 'myapp://seeded'") {
+		t.Error("a recorded deeplink should not be opened again")
+	}
+}
+
+func TestSeed_AndroidContactsAlreadyPresent(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "team.vcf"), []byte("BEGIN:VCARD\r\nFN:Jane Doe\r\nEND:VCARD\r\n"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "new.vcf"), []byte("BEGIN:VCARD\nFN;CHARSET=UTF-8:John Roe\nEND:VCARD\n"), 0o644)
+	manifest := writeSeedManifest(t, dir, "contacts:\n  - team.vcf\n  - new.vcf\n")
+
+	var pushed, shell []string
+	exec := androidRunExecutor(&pushed)
+	devices := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		joined := strings.Join(args, " ")
+		switch {
+		case strings.Contains(joined, "content query --uri content://com.android.contacts/raw_contacts"):
+			return []byte("Row: 0 display_name=Jane Doe\nRow: 1 display_name=NULL\n"), nil
+		case strings.Contains(joined, " shell "):
+			shell = append(shell, joined)
+			return []byte("Starting: Intent { }\n"), nil
+		}
+
+		return devices(name, args)
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.Seed("", manifest, cmd.SeedOptions{}); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+
+	want := "-s emulator-5554 push " + filepath.Join(dir, "new.vcf") + " /sdcard/Download/new.vcf"
+	if strings.Join(pushed, "\n") != want {
+		t.Errorf("expected only the missing contact to be pushed, got:\n%s", strings.Join(pushed, "\n"))
+	}
+	all := strings.Join(shell, "\n")
+	if strings.Contains(all, "team.vcf") {
+		t.Errorf("a contact already on the device should not be imported again:\n%s", all)
+	}
+	if strings.Contains(all, "contact:") {
+		t.Errorf("Android contact imports should not be recorded before they are confirmed:\n%s", all)
+	}
+}
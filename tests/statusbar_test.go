@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestStatusBarOverride_Android_DemoMode(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	o := cmd.StatusBarOverrides{Time: "9:41", Battery: 80, WiFi: 3, Cellular: 2}
+	if err := cmd.OverrideStatusBar("", o); err != nil {
+		t.Fatalf("OverrideStatusBar failed: %v", err)
+	}
+
+	const broadcast = "-s emulator-5554 shell am broadcast -a com.android.systemui.demo "
+	want := []string{
+		"-s emulator-5554 shell settings put global sysui_demo_allowed 1",
+		broadcast + "-e command enter",
+		broadcast + "-e command clock -e hhmm 0941",
+		broadcast + "-e command battery -e level 80 -e plugged false",
+		broadcast + "-e command network -e wifi show -e level 4",
+		broadcast + "-e command network -e mobile show -e datatype none -e level 2",
+		broadcast + "-e command notifications -e visible false",
+	}
+
+	if strings.Join(recorded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected demo mode commands:\ngot:\n%s\nwant:\n%s", strings.Join(recorded, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestStatusBarClear_Android_ExitsDemoMode(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.ClearStatusBar(""); err != nil {
+		t.Fatalf("ClearStatusBar failed: %v", err)
+	}
+
+	want := "-s emulator-5554 shell am broadcast -a com.android.systemui.demo -e command exit"
+	if len(recorded) != 1 || recorded[0] != want {
+		t.Errorf("expected %q, got %v", want, recorded)
+	}
+}
+
+func TestStatusBarOverrides_Validate(t *testing.T) {
+	if err := cmd.CleanStatusBar().Validate(); err != nil {
+		t.Errorf("clean status bar should be valid, got %v", err)
+	}
+
+	invalid := []cmd.StatusBarOverrides{
+		{Time: "9:41", Battery: 101, WiFi: 3, Cellular: 4},
+		{Time: "9:41", Battery: 100, WiFi: 4, Cellular: 4},
+		{Time: "9:41", Battery: 100, WiFi: 3, Cellular: 5},
+		{Time: "9.41", Battery: 100, WiFi: 3, Cellular: 4},
+	}
+	for _, o := range invalid {
+		if err := o.Validate(); !errors.Is(err, cmd.ErrInvalidStatusBar) {
+			t.Errorf("expected ErrInvalidStatusBar for %+v, got %v", o, err)
+		}
+	}
+}
+
+func TestStatusBarOverride_Android_Charging(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	cmd.SetExecutor(androidRunExecutor(&recorded))
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	o := cmd.CleanStatusBar()
+	o.Battery, o.Charging = 40, true
+	if err := cmd.OverrideStatusBar("", o); err != nil {
+		t.Fatalf("OverrideStatusBar failed: %v", err)
+	}
+
+	want := "-s emulator-5554 shell am broadcast -a com.android.systemui.demo -e command battery -e level 40 -e plugged true"
+	if !strings.Contains(strings.Join(recorded, "\n"), want) {
+		t.Errorf("expected %q in commands:\n%s", want, strings.Join(recorded, "\n"))
+	}
+}
+
+func TestScreenshot_CleanStatusBar_KeepsExistingDemoMode(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	var recorded []string
+	exec := androidRunExecutor(&recorded)
+	onOutput := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		if strings.HasSuffix(strings.Join(args, " "), "settings get global sysui_tuner_demo_on") {
+			return []byte("1\n"), nil
+		}
+
+		return onOutput(name, args)
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	root := cmd.GetRootCmd()
+	t.Cleanup(func() {
+		if c, _, err := root.Find([]string{"screenshot"}); err == nil {
+			_ = c.Flags().Set("clean-statusbar", "false")
+		}
+	})
+	root.SetArgs([]string{"screenshot", "emulator-5554", filepath.Join(t.TempDir(), "shot.png"), "--clean-statusbar"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("screenshot failed: %v", err)
+	}
+
+	var captured, overridden bool
+	for _, r := range recorded {
+		switch {
+		case strings.Contains(r, "screencap"):
+			captured = true
+		case strings.Contains(r, "command clock"):
+			overridden = true
+		case strings.Contains(r, "command exit"):
+			t.Errorf("expected the earlier demo mode to stay on, got %q", r)
+		}
+	}
+	if !captured || !overridden {
+		t.Errorf("expected a clean status bar capture, got %v", recorded)
+	}
+}
+
+func TestParseIOSStatusBarList(t *testing.T) {
+	list := `Current Status Bar Overrides:
+=============================
+Time: 9:41
+DataNetworkType: 5
+WiFi Mode: 3, WiFi Bars: 2
+Cell Mode: 3, Cell Bars: 4
+Operator Name: Carrier
+Battery State: 1, Battery Level: 100, Battery Detail Visible: 0
+`
+	want := []string{
+		"--time", "9:41",
+		"--dataNetwork", "lte-a",
+		"--wifiMode", "active", "--wifiBars", "2",
+		"--cellularMode", "active", "--cellularBars", "4",
+		"--operatorName", "Carrier",
+		"--batteryState", "charged", "--batteryLevel", "100",
+	}
+
+	got := cmd.ParseIOSStatusBarList(list)
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("ParseIOSStatusBarList() = %v, want %v", got, want)
+	}
+	if got := cmd.ParseIOSStatusBarList("Current Status Bar Overrides:\n=============================\n"); got != nil {
+		t.Errorf("expected no overrides, got %v", got)
+	}
+}
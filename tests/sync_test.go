@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annurdien/sim-cli/cmd"
+)
+
+func TestParseSyncTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		device  string
+		remote  string
+		wantErr bool
+	}{
+		{"/sdcard/assets", "", "/sdcard/assets", false},
+		{"Pixel_7:/sdcard/assets", "Pixel_7", "/sdcard/assets", false},
+		{"app:com.example.app/Documents", "", "app:com.example.app/Documents", false},
+		{"iPhone 15:app:com.example.app:group.com.example/x", "iPhone 15", "app:com.example.app:group.com.example/x", false},
+		{"Pixel_7:sdcard/assets", "", "", true},
+		{"assets", "", "", true},
+	}
+
+	for _, tt := range tests {
+		device, remote, err := cmd.ParseSyncTarget(tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSyncTarget(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			continue
+		}
+		if device != tt.device || remote != tt.remote {
+			t.Errorf("ParseSyncTarget(%q) = %q, %q; want %q, %q", tt.target, device, remote, tt.device, tt.remote)
+		}
+	}
+}
+
+func TestDiffSyncManifests(t *testing.T) {
+	local := cmd.SyncManifest{
+		"same.json":       {Size: 3, ModTime: 100},
+		"touched.json":    {Size: 3, ModTime: 200},
+		"grown.json":      {Size: 4, ModTime: 100},
+		"img/new.png":     {Size: 1, ModTime: 100},
+		"hashed/same.bin": {Size: 2, ModTime: 300, Sum: "aa"},
+		"hashed/edit.bin": {Size: 2, ModTime: 100, Sum: "bb"},
+	}
+	remote := cmd.SyncManifest{
+		"same.json":       {Size: 3, ModTime: 100},
+		"touched.json":    {Size: 3, ModTime: 100},
+		"grown.json":      {Size: 3, ModTime: 100},
+		"stale.json":      {Size: 1, ModTime: 100},
+		"hashed/same.bin": {Size: 2, ModTime: 100, Sum: "aa"},
+		"hashed/edit.bin": {Size: 2, ModTime: 100, Sum: "cc"},
+	}
+
+	changed, removed := cmd.DiffSyncManifests(local, remote)
+
+	wantChanged := []string{"grown.json", "hashed/edit.bin", "img/new.png", "touched.json"}
+	if !reflect.DeepEqual(changed, wantChanged) {
+		t.Errorf("changed = %v, want %v", changed, wantChanged)
+	}
+	if !reflect.DeepEqual(removed, []string{"stale.json"}) {
+		t.Errorf("removed = %v, want [stale.json]", removed)
+	}
+}
+
+func TestSync_Android(t *testing.T) {
+	_ = NewTestHelpers(t)
+
+	local := t.TempDir()
+	mtime := time.Unix(1700000000, 0)
+	for rel, data := range map[string]string{"same.json": "abc", "changed.json": "new", "img/new.png": "p"} {
+		p := filepath.Join(local, filepath.FromSlash(rel))
+		_ = os.MkdirAll(filepath.Dir(p), 0o755)
+		_ = os.WriteFile(p, []byte(data), 0o644)
+		_ = os.Chtimes(p, mtime, mtime)
+	}
+
+	var pushed, shell []string
+	exec := androidRunExecutor(&pushed)
+	devices := exec.onOutput
+	exec.onOutput = func(name string, args []string) ([]byte, error) {
+		joined := strings.Join(args, " ")
+		switch {
+		case strings.Contains(joined, "find '/sdcard/assets'"):
+			return []byte("3 1700000000 /sdcard/assets/same.json\n" +
+				"3 1600000000 /sdcard/assets/changed.json\n" +
+				"5 1600000000 /sdcard/assets/old/stale.json\n"), nil
+		case strings.Contains(joined, " shell "):
+			shell = append(shell, joined)
+			return []byte{}, nil
+		}
+
+		return devices(name, args)
+	}
+	cmd.SetExecutor(exec)
+	t.Cleanup(func() { cmd.SetExecutor(&cmd.OSCommandExecutor{}) })
+
+	if err := cmd.Sync("", local, "/sdcard/assets/", cmd.SyncOptions{Delete: true}); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	wantPushed := []string{
+		"-s emulator-5554 push " + filepath.Join(local, "changed.json") + " /sdcard/assets/",
+		"-s emulator-5554 push " + filepath.Join(local, "img", "new.png") + " /sdcard/assets/img/",
+	}
+	if !reflect.DeepEqual(pushed, wantPushed) {
+		t.Errorf("unexpected pushes:\n%s", strings.Join(pushed, "\n"))
+	}
+
+	wantShell := []string{
+		"-s emulator-5554 shell mkdir -p '/sdcard/assets' '/sdcard/assets/img'",
+		"-s emulator-5554 shell rm -f '/sdcard/assets/old/stale.json'",
+	}
+	if !reflect.DeepEqual(shell, wantShell) {
+		t.Errorf("unexpected shell commands:\n%s", strings.Join(shell, "\n"))
+	}
+}